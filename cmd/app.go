@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"unicode/utf8"
+
+	"domainweb/internal/config"
+	"domainweb/internal/dict"
+	"domainweb/internal/model"
+	"domainweb/internal/psl"
+	"domainweb/internal/repository"
+	"domainweb/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+// app 汇集各命令共用的配置、数据库连接和服务
+type app struct {
+	cfg              *config.Config
+	db               *sql.DB
+	domainService    *service.DomainService
+	historyService   *service.HistoryService
+	ruleSetService   *service.RuleSetService
+	catalog          *service.AttributeCatalog
+	auditService     *service.AuditService
+	jobService       *service.JobService
+	ruleService      *service.RuleService
+	attributeService *service.AttributeService
+	bundleService    *service.BundleService
+}
+
+// newApp 根据配置初始化数据库、存储库和服务
+func newApp(cfg *config.Config) (*app, error) {
+	// 加载公共后缀列表
+	suffixes, err := psl.Load(cfg.Domain.PublicSuffixFile, cfg.Domain.IncludePrivateSuffixes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 加载内置英文词典
+	words, err := dict.Default()
+	if err != nil {
+		return nil, err
+	}
+
+	// 初始化数据库连接
+	db, dialect, err := initDB(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("数据库初始化失败: %w", err)
+	}
+
+	// 应用数据库迁移，SQLite为本地嵌入式数据库，总是自动迁移以便零配置启动
+	// 迁移信息输出到标准错误，避免干扰命令的标准输出
+	if cfg.Database.AutoMigrate || dialect == repository.SQLite {
+		if err := applyMigrations(db, dialect, os.Stderr); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	// 初始化存储库
+	domainRepo := repository.NewDomainRepository(db, dialect)
+	historyRepo := repository.NewHistoryRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	ruleSetRepo := repository.NewRuleSetRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+
+	// 初始化服务
+	providers, err := service.NewDefaultProviderRegistry(cfg.Dynamic)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("动态属性数据源初始化失败: %w", err)
+	}
+	if err := providers.Configure(cfg.Dynamic.Providers); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("动态属性数据源配置无效: %w", err)
+	}
+	dynamicAttrService := service.NewDynamicAttributeService(providers, cfg.Dynamic.CacheTTLDuration(), cfg.Dynamic.TimeoutDuration())
+
+	auditService := service.NewAuditService(auditRepo)
+	catalog := service.NewAttributeCatalog(domainRepo, cfg.Estimation.AttributeRefreshDuration())
+	ruleSetService := service.NewRuleSetService(ruleSetRepo, catalog, auditService, cfg.Estimation)
+	domainService := service.NewDomainService(ruleSetService, dynamicAttrService, cfg.Estimation, suffixes, words)
+	domainService.SetClock(service.NewClock(cfg.Dynamic.MockMode))
+	historyService := service.NewHistoryService(historyRepo, cfg.Estimation.DefaultHistoryLimit)
+	ruleService := service.NewRuleService(domainRepo, providers, ruleSetService, auditService)
+
+	return &app{
+		cfg:              cfg,
+		db:               db,
+		domainService:    domainService,
+		historyService:   historyService,
+		ruleSetService:   ruleSetService,
+		catalog:          catalog,
+		auditService:     auditService,
+		jobService:       service.NewJobService(jobRepo, domainService, historyService, cfg.Jobs),
+		ruleService:      ruleService,
+		attributeService: service.NewAttributeService(domainRepo, domainService, ruleSetService, auditService),
+		bundleService:    service.NewBundleService(domainRepo, ruleService, ruleSetService, auditService),
+	}, nil
+}
+
+// withApp 加载配置并初始化服务，执行 fn 后关闭数据库连接
+func withApp(cmd *cobra.Command, fn func(a *app) error) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	return fn(a)
+}
+
+// cliChange 返回命令行修改规则时记录的操作者（当前系统用户）和 --reason 参数中的变更原因
+func cliChange(cmd *cobra.Command) (service.Change, error) {
+	reason, _ := cmd.Flags().GetString("reason")
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > service.MaxReasonLength {
+		return service.Change{}, fmt.Errorf("变更原因不能超过%d个字符", service.MaxReasonLength)
+	}
+
+	change := service.Change{Source: model.AuditSourceCLI, Reason: reason}
+	if u, err := user.Current(); err == nil {
+		change.Actor = u.Username
+	}
+	return change, nil
+}
+
+// Close 关闭数据库连接
+func (a *app) Close() error {
+	return a.db.Close()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"domainweb/internal/export"
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "批量估价文件中的域名",
+	Long:  "从文件中读取域名（每行一个，忽略空行和以 # 开头的注释行）并发估价，并将结果输出为CSV或JSON。并发数由 estimation.batchConcurrency 配置，单个域名估价失败时记录错误并继续处理。",
+	Example: `  domainweb batch -f names.txt -o results.csv
+  cat names.txt | domainweb batch -f - --format json`,
+	Args: cobra.NoArgs,
+	RunE: runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringP("file", "f", "", "域名列表文件，\"-\" 表示标准输入")
+	batchCmd.Flags().StringP("output", "o", "", "结果输出文件，默认输出到标准输出")
+	batchCmd.Flags().String("format", "csv", "输出格式：csv 或 json")
+	batchCmd.Flags().Bool("save-history", true, "将估价结果保存到查询历史")
+	batchCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// runBatch 读取域名列表，并发估价后输出结果
+func runBatch(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	saveHistory, _ := cmd.Flags().GetBool("save-history")
+	if err := checkFormat(format, "csv", "json"); err != nil {
+		return err
+	}
+
+	in, err := openInput(file)
+	if err != nil {
+		return err
+	}
+	domains, err := service.ReadDomainList(in)
+	in.Close()
+	if err != nil {
+		return err
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("域名列表为空: %s", file)
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	items, err := a.domainService.EstimateDomains(cmd.Context(), domains)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*model.EstimationResult, 0, len(items))
+	for _, item := range items {
+		if item.Result != nil {
+			results = append(results, item.Result)
+		}
+	}
+	if saveHistory {
+		if err := a.historyService.SaveHistories(cmd.Context(), results); err != nil {
+			fmt.Fprintf(os.Stderr, "保存查询历史失败: %v\n", err)
+		}
+	}
+
+	out, err := openOutput(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if format == "json" {
+		err = writeJSON(out, items)
+	} else {
+		err = export.WriteEstimationsCSV(out, items)
+	}
+	if err != nil {
+		return fmt.Errorf("输出估价结果失败: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "共估价 %d 个域名，失败 %d 个\n", len(items), len(items)-len(results))
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"domainweb/internal/model"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/idna"
+)
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate <域名>...",
+	Short: "估价一个或多个域名",
+	Example: `  domainweb estimate abc.com
+  domainweb estimate abc.com 中文.com --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEstimate,
+}
+
+func init() {
+	estimateCmd.Flags().Bool("json", false, "以JSON格式输出估价结果")
+	estimateCmd.Flags().Bool("save-history", true, "将估价结果保存到查询历史")
+	rootCmd.AddCommand(estimateCmd)
+}
+
+// runEstimate 依次估价参数中的域名并输出结果
+func runEstimate(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	saveHistory, _ := cmd.Flags().GetBool("save-history")
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	results := make([]*model.EstimationResult, 0, len(args))
+	for _, domain := range args {
+		result, err := a.domainService.EstimateDomain(cmd.Context(), domain)
+		if err != nil {
+			return fmt.Errorf("估价 %s 失败: %w", domain, err)
+		}
+		if saveHistory {
+			if err := a.historyService.SaveHistory(cmd.Context(), result); err != nil {
+				fmt.Fprintf(os.Stderr, "保存 %s 的查询历史失败: %v\n", domain, err)
+			}
+		}
+		results = append(results, result)
+	}
+
+	if asJSON {
+		// 单个域名时直接输出对象，便于脚本处理
+		if len(results) == 1 {
+			return writeJSON(os.Stdout, results[0])
+		}
+		return writeJSON(os.Stdout, results)
+	}
+
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printEstimation(os.Stdout, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printEstimation 以表格形式输出估价结果
+func printEstimation(w io.Writer, result *model.EstimationResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	// 国际化域名同时展示Unicode形式
+	if unicodeName, err := idna.Lookup.ToUnicode(result.Domain); err == nil && unicodeName != result.Domain {
+		fmt.Fprintf(tw, "域名:\t%s（%s）\n", unicodeName, result.Domain)
+	} else {
+		fmt.Fprintf(tw, "域名:\t%s\n", result.Domain)
+	}
+	fmt.Fprintf(tw, "品相等级:\t%.2f\n", result.Grade)
+	fmt.Fprintf(tw, "保守估价:\t%.2f 元\n", result.Price)
+
+	sections := []struct {
+		title string
+		attrs []model.AttributeDetail
+	}{
+		{"基础属性", result.BaseAttributes},
+		{"其他属性", result.OtherAttributes},
+	}
+	for _, section := range sections {
+		if len(section.attrs) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s:\n", section.title)
+		for _, attr := range section.attrs {
+			fmt.Fprintf(tw, "  %s\t%s\t×%.2f\t%+.2f\n", attr.Name, attr.Value, attr.PriceFactor, attr.GradeFactor)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"domainweb/internal/export"
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "管理查询历史",
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出查询历史",
+	Example: `  domainweb history export -o history.csv
+  domainweb history export --domain abc.com --limit 100 --format json`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryExport,
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <记录ID>",
+	Short: "按规则集版本重新估价历史记录",
+	Long:  "按记录估价时使用的规则集版本和/或当前规则重新估价历史记录，结果不保存到查询历史。按原始版本估价时使用记录保存的动态属性和参照时间，以复现记录的估价；按当前规则估价或记录没有保存输入时，动态属性按当前数据重新获取。",
+	Example: `  domainweb history rerun 42
+  domainweb history rerun 42 --against original`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryRerun,
+}
+
+func init() {
+	historyExportCmd.Flags().String("domain", "", "仅导出指定域名的历史记录")
+	historyExportCmd.Flags().Int("limit", 0, "导出的记录条数，默认使用 estimation.defaultHistoryLimit")
+	historyExportCmd.Flags().StringP("output", "o", "", "输出文件，默认输出到标准输出")
+	historyExportCmd.Flags().String("format", "csv", "输出格式：csv 或 json")
+	historyRerunCmd.Flags().String("against", service.RerunBoth, "使用的规则集：both、original 或 current")
+	historyCmd.AddCommand(historyExportCmd, historyRerunCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// runHistoryExport 导出查询历史记录
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	domain, _ := cmd.Flags().GetString("domain")
+	limit, _ := cmd.Flags().GetInt("limit")
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	if err := checkFormat(format, "csv", "json"); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	records, err := a.historyService.GetHistory(cmd.Context(), domain, limit)
+	if err != nil {
+		return fmt.Errorf("获取历史记录失败: %w", err)
+	}
+
+	out, err := openOutput(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if format == "json" {
+		// 没有记录时输出空数组而不是null
+		if records == nil {
+			records = []model.HistoryRecord{}
+		}
+		return writeJSON(out, records)
+	}
+	return export.WriteHistoryCSV(out, records)
+}
+
+// runHistoryRerun 重新估价历史记录并以JSON格式输出对比结果
+func runHistoryRerun(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("无效的记录ID: %s", args[0])
+	}
+	against, _ := cmd.Flags().GetString("against")
+	switch against {
+	case service.RerunBoth, service.RerunOriginal, service.RerunCurrent:
+	default:
+		return fmt.Errorf("--against 必须是 both、original 或 current")
+	}
+
+	return withApp(cmd, func(a *app) error {
+		record, err := a.historyService.Get(cmd.Context(), id)
+		if err != nil {
+			return err
+		}
+		result, err := a.domainService.Rerun(cmd.Context(), record, against)
+		if err != nil {
+			return err
+		}
+		return writeJSON(os.Stdout, result)
+	})
+}
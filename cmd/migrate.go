@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"domainweb/internal/migrate"
+	"domainweb/internal/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "管理数据库结构迁移",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "应用所有未执行的迁移",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd, func(db *sql.DB, dialect repository.Dialect, _ *migrate.Migrator) error {
+			return applyMigrations(db, dialect, os.Stdout)
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "回滚最近应用的迁移",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		steps, _ := cmd.Flags().GetInt("steps")
+		return withMigrator(cmd, func(_ *sql.DB, _ repository.Dialect, migrator *migrate.Migrator) error {
+			done, err := migrator.Down(steps)
+			for _, m := range done {
+				fmt.Printf("已回滚迁移 %04d_%s\n", m.Version, m.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if len(done) == 0 {
+				fmt.Println("没有可回滚的迁移")
+			}
+			return nil
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看迁移状态",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd, func(_ *sql.DB, _ repository.Dialect, migrator *migrate.Migrator) error {
+			return printMigrationStatus(migrator)
+		})
+	},
+}
+
+func init() {
+	migrateDownCmd.Flags().Int("steps", 1, "回滚的迁移数量")
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// withMigrator 加载配置并连接数据库后执行迁移操作
+// 迁移命令只需要数据库连接，不初始化估价服务
+func withMigrator(cmd *cobra.Command, fn func(*sql.DB, repository.Dialect, *migrate.Migrator) error) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	db, dialect, err := initDB(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("数据库初始化失败: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, dialect)
+	if err != nil {
+		return err
+	}
+	return fn(db, dialect, migrator)
+}
+
+// applyMigrations 应用所有未执行的迁移，并将结果输出到 out
+func applyMigrations(db *sql.DB, dialect repository.Dialect, out io.Writer) error {
+	migrator, err := migrate.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	done, err := migrator.Up()
+	for _, m := range done {
+		fmt.Fprintf(out, "已应用迁移 %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	return nil
+}
+
+// printMigrationStatus 以表格形式输出所有迁移的状态
+func printMigrationStatus(migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t应用时间")
+	for _, s := range statuses {
+		state, appliedAt := "未应用", "-"
+		if s.Applied {
+			state, appliedAt = "已应用", s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
@@ -1,126 +1,122 @@
 package cmd
 
 import (
-	"context"
 	"database/sql"
 	"fmt"
-	"html/template"
-	"log"
-	"net/http"
-	"os"
-	"os/signal"
-	"strings"
-	"syscall"
-	"time"
-
-	"domainweb/internal/api"
+
+	"domainweb/internal/config"
 	"domainweb/internal/repository"
-	"domainweb/internal/service"
 
-	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// 命令行参数到配置键的映射，仅覆盖显式指定的参数
+var configOverrides = map[string]string{
+	"host":        "server.host",
+	"port":        "server.port",
+	"db-host":     "database.host",
+	"db-port":     "database.port",
+	"db-user":     "database.username",
+	"db-password": "database.password",
+	"db-name":     "database.dbname",
+}
+
+// rootCmd 是所有命令的根命令，未指定子命令时启动Web服务
+var rootCmd = &cobra.Command{
+	Use:           "domainweb",
+	Short:         "域名估价系统",
+	Long:          "域名估价系统：提供Web服务，也可以在命令行中直接估价、批量估价和导出查询历史。",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServe(cmd, false)
+	},
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.String("config", "", "配置文件路径（默认 "+config.DefaultPath+"）")
+	flags.String("db-host", "", "数据库地址")
+	flags.Int("db-port", 0, "数据库端口")
+	flags.String("db-user", "", "数据库用户名")
+	flags.String("db-password", "", "数据库密码")
+	flags.String("db-name", "", "数据库名称")
+}
+
 // Execute 是应用程序的入口点
 func Execute() error {
-	// 初始化数据库连接
-	db, err := initDB()
+	return rootCmd.Execute()
+}
+
+// loadConfig 加载配置：配置文件 < 环境变量 < 命令行参数
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	configPath, err := cmd.Flags().GetString("config")
 	if err != nil {
-		return fmt.Errorf("数据库初始化失败: %w", err)
+		return nil, err
 	}
-	defer db.Close()
-
-	// 初始化存储库
-	domainRepo := repository.NewDomainRepository(db)
-	historyRepo := repository.NewHistoryRepository(db)
-
-	// 初始化服务
-	domainService := service.NewDomainService(domainRepo)
-	historyService := service.NewHistoryService(historyRepo)
-
-	// 设置Gin路由
-	router := setupRouter(domainService, historyService)
 
-	// 创建HTTP服务器
-	srv := &http.Server{
-		Addr:    ":8080",
-		Handler: router,
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
 	}
 
-	// 在goroutine中启动服务器
-	go func() {
-		fmt.Println("服务器启动在 http://localhost:8080")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("监听失败: %s\n", err)
+	var setErr error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		key, ok := configOverrides[f.Name]
+		if !ok || setErr != nil {
+			return
 		}
-	}()
-
-	// 等待中断信号以优雅地关闭服务器
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("关闭服务器...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("服务器强制关闭:", err)
+		if err := cfg.Set(key, f.Value.String()); err != nil {
+			setErr = fmt.Errorf("参数 --%s 无效: %w", f.Name, err)
+		}
+	})
+	if setErr != nil {
+		return nil, setErr
 	}
 
-	log.Println("服务器优雅退出")
-	return nil
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // 初始化数据库连接
-func initDB() (*sql.DB, error) {
-	// 从环境变量或配置文件获取数据库连接信息
-	// 这里使用硬编码的连接信息作为示例
-	dsn := "sfs:d3v777aaa@tcp(127.0.0.1:3306)/domainweb?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := sql.Open("mysql", dsn)
+func initDB(cfg config.DatabaseConfig) (*sql.DB, repository.Dialect, error) {
+	dialect, err := repository.ParseDialect(cfg.Driver)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	var db *sql.DB
+	switch dialect {
+	case repository.SQLite:
+		db, err = repository.OpenSQLite(cfg.Path)
+	default:
+		db, err = sql.Open(dialect.DriverName(), cfg.DSN())
+	}
+	if err != nil {
+		return nil, "", err
 	}
 
 	// 测试数据库连接
 	if err := db.Ping(); err != nil {
-		return nil, err
+		db.Close()
+		return nil, "", err
 	}
 
 	// 设置连接池参数
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(time.Hour)
-
-	return db, nil
-}
-
-// 设置Gin路由
-func setupRouter(domainService *service.DomainService, historyService *service.HistoryService) *gin.Engine {
-	router := gin.Default()
-
-	// 加载HTML模板并添加自定义函数
-	router.SetFuncMap(template.FuncMap{
-		"contains": strings.Contains,
-	})
-	router.LoadHTMLGlob("web/templates/*")
-
-	// 提供静态文件
-	router.Static("/static", "./web/static")
-
-	// 设置API处理器
-	handler := api.NewHandler(domainService, historyService)
-
-	// 定义路由
-	router.GET("/", handler.HomePage)
-	router.POST("/estimate", handler.EstimateDomain)
-	router.GET("/history", handler.GetHistory)
-
-	// API路由
-	apiGroup := router.Group("/api")
-	{
-		apiGroup.POST("/estimate", handler.APIEstimateDomain)
-		apiGroup.GET("/history", handler.APIGetHistory)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetimeDuration())
+	if dialect == repository.SQLite {
+		// SQLite同一时间只允许一个写入者，使用单个常驻连接避免锁冲突，
+		// 同时保证内存数据库不会因连接全部关闭而被销毁
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
 	}
 
-	return router
+	return db, dialect, nil
 }
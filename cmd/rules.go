@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"domainweb/internal/bundle"
+	"domainweb/internal/model"
+	"domainweb/internal/rules"
+	"domainweb/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "管理动态属性估价规则",
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有估价规则",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return withApp(cmd, func(a *app) error {
+			list, err := a.ruleService.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				if list == nil {
+					list = []model.ValuationRule{}
+				}
+				return writeJSON(os.Stdout, list)
+			}
+			return printRules(os.Stdout, list)
+		})
+	},
+}
+
+var rulesValidateCmd = &cobra.Command{
+	Use:   "validate <表达式>",
+	Short: "检查条件表达式",
+	Example: `  domainweb rules validate 'length <= 4 && structure == "纯字母" && tld in ["com", "net"]'
+  domainweb rules validate 'search_volume / alexa_rank > 0.5'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withApp(cmd, func(a *app) error {
+			program, err := a.ruleService.Compile(args[0])
+			if err != nil {
+				return fmt.Errorf("表达式无效: %w", err)
+			}
+			fmt.Printf("表达式有效，引用的变量: %s\n", strings.Join(program.Variables(), ", "))
+			return nil
+		})
+	},
+}
+
+var rulesAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "添加估价规则",
+	Example: `  domainweb rules add --key short_premium --label 短域名精品 --price 3 --grade 0.8 \
+    --expr 'length <= 4 && structure == "纯字母" && tld in ["com", "net"]'
+  domainweb rules add --key baike_index --op '>' --value 1000 --label 百科指数较高 --price 1.5 --grade 0.3`,
+	Args: cobra.NoArgs,
+	RunE: runRulesAdd,
+}
+
+var rulesDeleteCmd = &cobra.Command{
+	Use:   "delete <规则ID>",
+	Short: "删除估价规则",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("无效的规则ID: %s", args[0])
+		}
+		change, err := cliChange(cmd)
+		if err != nil {
+			return err
+		}
+		return withApp(cmd, func(a *app) error {
+			if err := a.ruleService.Delete(cmd.Context(), id, change); err != nil {
+				return err
+			}
+			fmt.Printf("已删除估价规则 %d\n", id)
+			return nil
+		})
+	},
+}
+
+var rulesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出域名属性和估价规则为规则包",
+	Example: `  domainweb rules export -o rules.yaml
+  domainweb rules export --format json > rules.json`,
+	Args: cobra.NoArgs,
+	RunE: runRulesExport,
+}
+
+var rulesImportCmd = &cobra.Command{
+	Use:   "import <文件>",
+	Short: "导入规则包，取代当前的域名属性和估价规则",
+	Long:  "导入规则包，取代当前的域名属性和估价规则：新增缺少的记录、修改不同的记录并删除规则包中没有的记录。文件为 - 时从标准输入读取。",
+	Example: `  domainweb rules import rules.yaml --dry-run
+  domainweb rules import rules.yaml --reason "同步海外市场的估价倍数"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRulesImport,
+}
+
+func init() {
+	rulesListCmd.Flags().Bool("json", false, "以JSON格式输出")
+
+	flags := rulesAddCmd.Flags()
+	flags.String("key", "", "属性键，表达式规则中用于分组，同一属性键只有第一条满足条件的规则生效")
+	flags.String("op", "", "运算符："+strings.Join(rules.Operators, "、")+"，指定 --expr 时默认为 expr")
+	flags.String("value", "", "比较值")
+	flags.Float64("min", 0, "range 运算的下限（含）")
+	flags.Float64("max", 0, "range 运算的上限（不含）")
+	flags.String("expr", "", "条件表达式")
+	flags.Float64("price", 1, "估价倍数")
+	flags.Float64("grade", 0, "等级增量")
+	flags.String("label", "", "属性名称模板")
+	flags.String("description", "", "属性描述模板")
+	flags.Int("priority", 0, "优先级，数值小的先匹配")
+	flags.Bool("disabled", false, "添加为未启用的规则")
+	flags.String("reason", "", "变更原因，记录到审计日志")
+	rulesAddCmd.MarkFlagRequired("key")
+	rulesAddCmd.MarkFlagRequired("label")
+
+	rulesDeleteCmd.Flags().String("reason", "", "变更原因，记录到审计日志")
+
+	rulesExportCmd.Flags().StringP("output", "o", "", "输出文件，默认输出到标准输出")
+	rulesExportCmd.Flags().String("format", "", "规则包格式：yaml 或 json，默认按输出文件的扩展名判断，无法判断时为 yaml")
+
+	rulesImportCmd.Flags().Bool("dry-run", false, "只显示与当前数据库的差异，不修改数据库")
+	rulesImportCmd.Flags().String("format", "", "规则包格式：yaml 或 json，默认按文件扩展名判断")
+	rulesImportCmd.Flags().String("reason", "", "变更原因，记录到审计日志")
+	rulesImportCmd.Flags().Bool("json", false, "以JSON格式输出导入结果")
+
+	rulesCmd.AddCommand(rulesListCmd, rulesValidateCmd, rulesAddCmd, rulesDeleteCmd, rulesExportCmd, rulesImportCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
+
+// runRulesAdd 校验并保存命令行参数描述的估价规则
+func runRulesAdd(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	rule := model.ValuationRule{}
+	rule.AttributeKey, _ = flags.GetString("key")
+	rule.Operator, _ = flags.GetString("op")
+	rule.Value, _ = flags.GetString("value")
+	rule.Expression, _ = flags.GetString("expr")
+	rule.PriceFactor, _ = flags.GetFloat64("price")
+	rule.GradeFactor, _ = flags.GetFloat64("grade")
+	rule.Label, _ = flags.GetString("label")
+	rule.Description, _ = flags.GetString("description")
+	rule.Priority, _ = flags.GetInt("priority")
+	disabled, _ := flags.GetBool("disabled")
+	rule.Enabled = !disabled
+
+	if rule.Operator == "" && rule.Expression != "" {
+		rule.Operator = rules.OpExpr
+	}
+	if flags.Changed("min") {
+		v, _ := flags.GetFloat64("min")
+		rule.MinValue = &v
+	}
+	if flags.Changed("max") {
+		v, _ := flags.GetFloat64("max")
+		rule.MaxValue = &v
+	}
+
+	change, err := cliChange(cmd)
+	if err != nil {
+		return err
+	}
+	return withApp(cmd, func(a *app) error {
+		if err := a.ruleService.Create(cmd.Context(), &rule, change); err != nil {
+			return err
+		}
+		fmt.Printf("已添加估价规则 %d\n", rule.ID)
+		return nil
+	})
+}
+
+// printRules 以表格形式输出估价规则
+func printRules(w io.Writer, list []model.ValuationRule) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\t属性键\t条件\t估价倍数\t等级增量\t名称\t优先级\t启用")
+	for _, rule := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.2f\t%+.2f\t%s\t%d\t%t\n",
+			rule.ID, rule.AttributeKey, ruleCondition(rule), rule.PriceFactor, rule.GradeFactor,
+			rule.Label, rule.Priority, rule.Enabled)
+	}
+	return tw.Flush()
+}
+
+// ruleCondition 返回规则条件的文本形式
+func ruleCondition(rule model.ValuationRule) string {
+	switch rule.Operator {
+	case rules.OpExpr:
+		return rule.Expression
+	case rules.OpExists:
+		return rule.Operator
+	case rules.OpRange:
+		min, max := "-∞", "+∞"
+		if rule.MinValue != nil {
+			min = strconv.FormatFloat(*rule.MinValue, 'f', -1, 64)
+		}
+		if rule.MaxValue != nil {
+			max = strconv.FormatFloat(*rule.MaxValue, 'f', -1, 64)
+		}
+		return fmt.Sprintf("[%s, %s)", min, max)
+	}
+	return rule.Operator + " " + rule.Value
+}
+
+// runRulesExport 导出规则包
+func runRulesExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = bundle.FormatFromPath(output)
+	}
+	if err := bundle.CheckFormat(format); err != nil {
+		return err
+	}
+
+	return withApp(cmd, func(a *app) error {
+		b, err := a.bundleService.Export(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		out, err := openOutput(output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		return bundle.Encode(out, b, format)
+	})
+}
+
+// runRulesImport 导入规则包并输出差异
+func runRulesImport(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = bundle.FormatFromPath(args[0])
+	}
+	if err := bundle.CheckFormat(format); err != nil {
+		return err
+	}
+	change, err := cliChange(cmd)
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(args[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	b, err := bundle.Decode(in, format)
+	if err != nil {
+		return err
+	}
+
+	return withApp(cmd, func(a *app) error {
+		result, err := a.bundleService.Import(cmd.Context(), b, change, dryRun)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return writeJSON(os.Stdout, result)
+		}
+		return printImportResult(os.Stdout, result)
+	})
+}
+
+// printImportResult 逐项输出导入规则包的差异和结果
+func printImportResult(w io.Writer, result *service.ImportResult) error {
+	fmt.Fprintf(w, "域名属性：%s\n", summaryText(result.Attributes))
+	for _, c := range result.Diff.Attributes {
+		switch c.Action {
+		case bundle.ActionCreate:
+			fmt.Fprintf(w, "  + %s（%s %s）×%.2f %+.2f\n", c.After.AttributeName, c.After.AttributeType, c.After.AttributeValue, c.After.PriceFactor, c.After.GradeFactor)
+		case bundle.ActionUpdate:
+			fmt.Fprintf(w, "  ~ %s（ID %d）%s\n", c.After.AttributeName, c.After.ID, fieldsText(c.Fields))
+		case bundle.ActionDelete:
+			fmt.Fprintf(w, "  - %s（ID %d）\n", c.Before.AttributeName, c.Before.ID)
+		}
+	}
+
+	fmt.Fprintf(w, "估价规则：%s\n", summaryText(result.ValuationRules))
+	for _, c := range result.Diff.ValuationRules {
+		switch c.Action {
+		case bundle.ActionCreate:
+			fmt.Fprintf(w, "  + %s %s %s\n", c.After.AttributeKey, ruleCondition(*c.After), c.After.Label)
+		case bundle.ActionUpdate:
+			fmt.Fprintf(w, "  ~ %s %s（ID %d）%s\n", c.After.AttributeKey, ruleCondition(*c.After), c.After.ID, fieldsText(c.Fields))
+		case bundle.ActionDelete:
+			fmt.Fprintf(w, "  - %s %s（ID %d）\n", c.Before.AttributeKey, ruleCondition(*c.Before), c.Before.ID)
+		}
+	}
+
+	switch {
+	case result.Diff.Empty():
+		fmt.Fprintln(w, "规则包与当前数据库一致，没有需要导入的变更")
+	case result.DryRun:
+		fmt.Fprintln(w, "预演模式，未修改数据库")
+	default:
+		fmt.Fprintf(w, "已导入，当前规则集版本 %d\n", result.RuleSetVersion)
+	}
+	return nil
+}
+
+// summaryText 返回变更数量的说明
+func summaryText(s bundle.Summary) string {
+	return fmt.Sprintf("新增 %d，修改 %d，删除 %d，未变化 %d", s.Created, s.Updated, s.Deleted, s.Unchanged)
+}
+
+// fieldsText 返回修改的字段及其前后的值，如 priceFactor 9.55 → 10
+func fieldsText(fields []bundle.FieldChange) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = fmt.Sprintf("%s %s → %s", f.Field, f.Before, f.After)
+	}
+	return strings.Join(parts, "，")
+}
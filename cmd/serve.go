@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"domainweb/internal/api"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "启动Web服务",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		migrate, err := cmd.Flags().GetBool("migrate")
+		if err != nil {
+			return err
+		}
+		return runServe(cmd, migrate)
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("host", "", "服务器监听地址")
+	serveCmd.Flags().Int("port", 0, "服务器监听端口")
+	serveCmd.Flags().Bool("migrate", false, "启动前应用未执行的数据库迁移（同 database.autoMigrate）")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe 启动Web服务并等待中断信号
+func runServe(cmd *cobra.Command, migrate bool) error {
+	fmt.Println("域名估价系统启动中...")
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if migrate {
+		cfg.Database.AutoMigrate = true
+	}
+
+	a, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	// 继续执行上次退出时未完成的估价任务
+	resumed, err := a.jobService.Resume(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("恢复估价任务失败: %w", err)
+	}
+	if resumed > 0 {
+		log.Printf("已恢复 %d 个未完成的估价任务", resumed)
+	}
+
+	// 设置Gin路由
+	router := setupRouter(a)
+	if !cfg.Admin.Enabled() {
+		log.Println("未配置 admin.username 和 admin.password，管理后台未启用")
+	}
+
+	// 创建HTTP服务器
+	srv := &http.Server{
+		Addr:           cfg.Server.Addr(),
+		Handler:        router,
+		ReadTimeout:    cfg.Server.ReadTimeoutDuration(),
+		WriteTimeout:   cfg.Server.WriteTimeoutDuration(),
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+	}
+
+	// 在goroutine中启动服务器
+	go func() {
+		fmt.Printf("服务器启动在 http://localhost:%d\n", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("监听失败: %s\n", err)
+		}
+	}()
+
+	// 等待中断信号以优雅地关闭服务器
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("关闭服务器...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("服务器强制关闭:", err)
+	}
+
+	// 停止执行中的估价任务，未完成的部分在下次启动时继续
+	if err := a.jobService.Shutdown(ctx); err != nil {
+		log.Println("等待估价任务停止超时:", err)
+	}
+
+	log.Println("服务器优雅退出")
+	return nil
+}
+
+// 设置Gin路由
+func setupRouter(a *app) *gin.Engine {
+	router := gin.Default()
+
+	// 加载HTML模板并添加自定义函数
+	router.SetFuncMap(template.FuncMap{
+		"contains": strings.Contains,
+	})
+	router.LoadHTMLGlob("web/templates/*")
+
+	// 提供静态文件
+	router.Static("/static", "./web/static")
+
+	// 设置API处理器
+	handler := api.NewHandler(a.domainService, a.historyService)
+	jobHandler := api.NewJobHandler(a.jobService)
+	ruleSetHandler := api.NewRuleSetHandler(a.domainService, a.historyService, a.ruleSetService)
+	catalogHandler := api.NewCatalogHandler(a.catalog)
+
+	// 定义路由
+	router.GET("/", handler.HomePage)
+	router.POST("/estimate", handler.EstimateDomain)
+	router.GET("/history", handler.GetHistory)
+
+	// API路由
+	apiGroup := router.Group("/api")
+	{
+		apiGroup.POST("/estimate", handler.APIEstimateDomain)
+		apiGroup.POST("/estimate/batch", handler.APIEstimateBatch)
+		apiGroup.GET("/history", handler.APIGetHistory)
+		apiGroup.POST("/history/:id/rerun", ruleSetHandler.RerunHistory)
+
+		// 规则集版本
+		apiGroup.GET("/rulesets", ruleSetHandler.ListRuleSets)
+		apiGroup.GET("/rulesets/:version", ruleSetHandler.GetRuleSet)
+
+		// 域名属性缓存的统计信息
+		apiGroup.GET("/catalog/stats", catalogHandler.GetStats)
+
+		// 异步估价任务
+		apiGroup.POST("/jobs", jobHandler.CreateJob)
+		apiGroup.GET("/jobs/:id", jobHandler.GetJob)
+		apiGroup.POST("/jobs/:id/cancel", jobHandler.CancelJob)
+		apiGroup.GET("/jobs/:id/results", jobHandler.GetJobResults)
+	}
+
+	// 管理后台，需要配置管理员账号后才会启用
+	if admin := a.cfg.Admin; admin.Enabled() {
+		adminHandler := api.NewAdminHandler(a.attributeService)
+		auditHandler := api.NewAuditHandler(a.auditService, a.attributeService)
+		basicAuth := gin.BasicAuth(gin.Accounts{admin.Username: admin.Password})
+		adminGroup := router.Group("/admin", basicAuth, api.RequireSameOrigin())
+		{
+			adminGroup.GET("", func(c *gin.Context) {
+				c.Redirect(http.StatusFound, "/admin/attributes")
+			})
+			adminGroup.GET("/attributes", adminHandler.ListAttributes)
+			adminGroup.GET("/attributes/new", adminHandler.NewAttribute)
+			adminGroup.POST("/attributes", adminHandler.SaveAttribute)
+			adminGroup.GET("/attributes/:id", adminHandler.EditAttribute)
+			adminGroup.POST("/attributes/:id", adminHandler.SaveAttribute)
+			adminGroup.POST("/attributes/:id/delete", adminHandler.DeleteAttribute)
+			adminGroup.GET("/audit", auditHandler.ListAuditLogs)
+		}
+
+		// 审计日志包含操作者信息，与管理后台使用相同的认证
+		router.GET("/api/audit", basicAuth, auditHandler.APIListAuditLogs)
+
+		// 导入规则包会取代所有域名属性和估价规则，同样需要管理员认证
+		bundleHandler := api.NewBundleHandler(a.bundleService)
+		rulesGroup := router.Group("/api/rules", basicAuth, api.RequireSameOrigin())
+		{
+			rulesGroup.GET("/export", bundleHandler.ExportRules)
+			rulesGroup.POST("/import", bundleHandler.ImportRules)
+		}
+	}
+
+	return router
+}
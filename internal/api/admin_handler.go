@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 列表页展示的操作结果提示
+var adminMessages = map[string]string{
+	"created": "域名属性已添加",
+	"updated": "域名属性已保存",
+	"deleted": "域名属性已删除",
+}
+
+// AdminHandler 处理管理后台的HTTP请求
+type AdminHandler struct {
+	attributeService *service.AttributeService
+}
+
+// NewAdminHandler 创建一个新的AdminHandler实例
+func NewAdminHandler(attributeService *service.AttributeService) *AdminHandler {
+	return &AdminHandler{attributeService: attributeService}
+}
+
+// RequireSameOrigin 拒绝来自其他站点的修改请求，防止跨站请求伪造
+// 浏览器会自动附带基本认证的凭据，因此仅靠认证无法阻止其他页面提交的表单
+func RequireSameOrigin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			origin = c.GetHeader("Referer")
+		}
+		if origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != c.Request.Host {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "拒绝跨站请求"})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// ListAttributes 显示所有域名属性
+func (h *AdminHandler) ListAttributes(c *gin.Context) {
+	attrs, err := h.attributeService.List(c.Request.Context())
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "获取域名属性失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "admin_attributes.html", gin.H{
+		"title":      "域名属性管理",
+		"attributes": attrs,
+		"message":    adminMessages[c.Query("msg")],
+	})
+}
+
+// NewAttribute 显示新增域名属性的表单
+func (h *AdminHandler) NewAttribute(c *gin.Context) {
+	h.renderForm(c, http.StatusOK, model.DomainAttribute{AttributeType: model.AttributeTypeBase, PriceFactor: 1}, "", nil, "")
+}
+
+// EditAttribute 显示编辑域名属性的表单
+func (h *AdminHandler) EditAttribute(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	attr, err := h.attributeService.Get(c.Request.Context(), id)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	h.renderForm(c, http.StatusOK, *attr, "", nil, "")
+}
+
+// SaveAttribute 新增或更新域名属性，表单的 action 为 preview 时只预览不保存
+func (h *AdminHandler) SaveAttribute(c *gin.Context) {
+	var id int64
+	if c.Param("id") != "" {
+		var ok bool
+		if id, ok = parseID(c); !ok {
+			return
+		}
+	}
+
+	attr, err := bindAttributeForm(c)
+	attr.ID = id
+	if err != nil {
+		h.renderForm(c, http.StatusBadRequest, attr, "", nil, err.Error())
+		return
+	}
+
+	if c.PostForm("action") == "preview" {
+		domain := strings.TrimSpace(c.PostForm("preview_domain"))
+		if domain == "" {
+			h.renderForm(c, http.StatusBadRequest, attr, domain, nil, "请输入用于预览的域名")
+			return
+		}
+		preview, err := h.attributeService.Preview(c.Request.Context(), domain, attr)
+		if err != nil {
+			h.renderForm(c, statusOf(err), attr, domain, nil, err.Error())
+			return
+		}
+		h.renderForm(c, http.StatusOK, attr, domain, preview, "")
+		return
+	}
+
+	change, err := adminChange(c)
+	if err != nil {
+		h.renderForm(c, http.StatusBadRequest, attr, c.PostForm("preview_domain"), nil, err.Error())
+		return
+	}
+
+	msg := "updated"
+	if id == 0 {
+		msg = "created"
+		err = h.attributeService.Create(c.Request.Context(), &attr, change)
+	} else {
+		err = h.attributeService.Update(c.Request.Context(), &attr, change)
+	}
+	if err != nil {
+		h.renderForm(c, statusOf(err), attr, c.PostForm("preview_domain"), nil, err.Error())
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/admin/attributes?msg="+msg)
+}
+
+// DeleteAttribute 删除域名属性
+func (h *AdminHandler) DeleteAttribute(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	change, err := adminChange(c)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := h.attributeService.Delete(c.Request.Context(), id, change); err != nil {
+		h.renderError(c, err)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/admin/attributes?msg=deleted")
+}
+
+// renderForm 显示域名属性表单，preview 不为空时同时显示修改前后的估价对比
+func (h *AdminHandler) renderForm(c *gin.Context, status int, attr model.DomainAttribute, previewDomain string, preview *service.AttributePreview, errMsg string) {
+	title := "新增域名属性"
+	if attr.ID != 0 {
+		title = "编辑域名属性"
+	}
+	c.HTML(status, "admin_attribute_form.html", gin.H{
+		"title":         title,
+		"attr":          attr,
+		"types":         service.AttributeTypes,
+		"previewDomain": previewDomain,
+		"preview":       preview,
+		"reason":        c.PostForm("reason"),
+		"error":         errMsg,
+	})
+}
+
+// renderError 按错误类型显示错误页
+func (h *AdminHandler) renderError(c *gin.Context, err error) {
+	c.HTML(statusOf(err), "error.html", gin.H{
+		"error": err.Error(),
+	})
+}
+
+// statusOf 返回错误对应的HTTP状态码
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, service.ErrAttributeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidAttribute):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// adminChange 返回通过基本认证登录的管理员和表单中填写的变更原因
+func adminChange(c *gin.Context) (service.Change, error) {
+	reason := strings.TrimSpace(c.PostForm("reason"))
+	if utf8.RuneCountInString(reason) > service.MaxReasonLength {
+		return service.Change{}, fmt.Errorf("变更原因不能超过%d个字符", service.MaxReasonLength)
+	}
+	return service.Change{
+		Actor:  c.GetString(gin.AuthUserKey),
+		Source: model.AuditSourceAdmin,
+		Reason: reason,
+	}, nil
+}
+
+// parseID 解析路径中的属性ID，无效时直接返回错误页
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": "无效的属性ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// bindAttributeForm 从表单读取域名属性，数字格式错误时仍返回已读取的内容以便重新显示表单
+func bindAttributeForm(c *gin.Context) (model.DomainAttribute, error) {
+	attr := model.DomainAttribute{
+		AttributeName:  c.PostForm("attribute_name"),
+		AttributeType:  c.PostForm("attribute_type"),
+		AttributeValue: c.PostForm("attribute_value"),
+	}
+
+	var err error
+	if attr.PriceFactor, err = strconv.ParseFloat(strings.TrimSpace(c.PostForm("price_factor")), 64); err != nil {
+		return attr, errors.New("估价倍数必须是数字")
+	}
+	if attr.GradeFactor, err = strconv.ParseFloat(strings.TrimSpace(c.PostForm("grade_factor")), 64); err != nil {
+		return attr, errors.New("等级增量必须是数字")
+	}
+	if attr.MinValue, err = optionalFloatForm(c, "min_value"); err != nil {
+		return attr, errors.New("取值范围的下限必须是数字")
+	}
+	if attr.MaxValue, err = optionalFloatForm(c, "max_value"); err != nil {
+		return attr, errors.New("取值范围的上限必须是数字")
+	}
+	return attr, nil
+}
+
+// optionalFloatForm 读取可选的数字表单字段，为空时返回 nil
+func optionalFloatForm(c *gin.Context, name string) (*float64, error) {
+	value := strings.TrimSpace(c.PostForm(name))
+	if value == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 审计日志页面显示的名称
+var (
+	auditEntityNames = map[string]string{
+		model.AuditEntityAttribute: "域名属性",
+		model.AuditEntityRule:      "估价规则",
+		model.AuditEntityRuleSet:   "规则集",
+	}
+	auditActionNames = map[string]string{
+		model.AuditActionCreate: "新增",
+		model.AuditActionUpdate: "修改",
+		model.AuditActionDelete: "删除",
+	}
+	auditSourceNames = map[string]string{
+		model.AuditSourceAdmin:  "管理后台",
+		model.AuditSourceAPI:    "API",
+		model.AuditSourceCLI:    "命令行",
+		model.AuditSourceSystem: "系统",
+	}
+)
+
+// AuditHandler 处理审计日志查询请求
+type AuditHandler struct {
+	auditService     *service.AuditService
+	attributeService *service.AttributeService
+}
+
+// NewAuditHandler 创建一个新的AuditHandler实例
+func NewAuditHandler(auditService *service.AuditService, attributeService *service.AttributeService) *AuditHandler {
+	return &AuditHandler{auditService: auditService, attributeService: attributeService}
+}
+
+// auditRow 是审计日志页面的一行，Changes 列出变更前后不同的字段
+type auditRow struct {
+	model.AuditLog
+	Changes []auditFieldChange
+}
+
+// auditFieldChange 表示一个字段变更前后的值
+type auditFieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// APIListAuditLogs 按条件查询审计日志（API）
+func (h *AuditHandler) APIListAuditLogs(c *gin.Context) {
+	filter, err := bindAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []model.AuditLog{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// ListAuditLogs 显示审计日志页面，可按域名属性、对象类型和日期筛选
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	attrs, err := h.attributeService.List(c.Request.Context())
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "获取域名属性失败: " + err.Error(),
+		})
+		return
+	}
+
+	data := gin.H{
+		"title":       "变更记录",
+		"attributes":  attrs,
+		"entityNames": auditEntityNames,
+		"actionNames": auditActionNames,
+		"sourceNames": auditSourceNames,
+		"query": gin.H{
+			"attribute":   c.Query("attribute"),
+			"entity_type": c.Query("entity_type"),
+			"name":        c.Query("name"),
+			"from":        c.Query("from"),
+			"to":          c.Query("to"),
+		},
+	}
+
+	filter, err := bindAuditFilter(c)
+	if err != nil {
+		data["error"] = err.Error()
+		c.HTML(http.StatusBadRequest, "admin_audit.html", data)
+		return
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		data["error"] = "获取变更记录失败: " + err.Error()
+		c.HTML(http.StatusInternalServerError, "admin_audit.html", data)
+		return
+	}
+
+	rows := make([]auditRow, len(entries))
+	for i, entry := range entries {
+		rows[i] = auditRow{AuditLog: entry, Changes: diffAuditValues(entry.Before, entry.After)}
+	}
+	data["rows"] = rows
+	c.HTML(http.StatusOK, "admin_audit.html", data)
+}
+
+// bindAuditFilter 从查询参数读取筛选条件
+// attribute 为域名属性ID，from 和 to 为 2006-01-02 格式的日期（均包含当天）
+func bindAuditFilter(c *gin.Context) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		EntityName: c.Query("name"),
+	}
+	if filter.EntityType != "" {
+		if _, ok := auditEntityNames[filter.EntityType]; !ok {
+			return filter, fmt.Errorf("不支持的对象类型: %s", filter.EntityType)
+		}
+	}
+
+	if v := c.Query("entity_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, errors.New("无效的对象ID")
+		}
+		filter.EntityID = id
+	}
+	if v := c.Query("attribute"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, errors.New("无效的属性ID")
+		}
+		filter.EntityType = model.AuditEntityAttribute
+		filter.EntityID = id
+	}
+
+	if v := c.Query("from"); v != "" {
+		from, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return filter, errors.New("起始日期格式应为 2006-01-02")
+		}
+		filter.From = from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return filter, errors.New("截止日期格式应为 2006-01-02")
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, errors.New("无效的条数")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// diffAuditValues 比较变更前后的JSON对象，返回值不同的字段，按字段名排序
+func diffAuditValues(before, after json.RawMessage) []auditFieldChange {
+	var beforeFields, afterFields map[string]json.RawMessage
+	json.Unmarshal(before, &beforeFields)
+	json.Unmarshal(after, &afterFields)
+
+	fields := make(map[string]bool)
+	for field := range beforeFields {
+		fields[field] = true
+	}
+	for field := range afterFields {
+		fields[field] = true
+	}
+
+	var changes []auditFieldChange
+	for field := range fields {
+		b, a := beforeFields[field], afterFields[field]
+		if bytes.Equal(b, a) {
+			continue
+		}
+		changes = append(changes, auditFieldChange{Field: field, Before: auditValueString(b), After: auditValueString(a)})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// auditValueString 将JSON值转换为便于阅读的文本，字符串去掉引号
+func auditValueString(value json.RawMessage) string {
+	if len(value) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		return s
+	}
+	return string(value)
+}
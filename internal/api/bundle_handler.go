@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"domainweb/internal/bundle"
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// BundleHandler 处理规则包导出和导入请求
+type BundleHandler struct {
+	bundleService *service.BundleService
+}
+
+// NewBundleHandler 创建一个新的BundleHandler实例
+func NewBundleHandler(bundleService *service.BundleService) *BundleHandler {
+	return &BundleHandler{bundleService: bundleService}
+}
+
+// ExportRules 导出规则包，format 参数可选 yaml（默认）或 json
+func (h *BundleHandler) ExportRules(c *gin.Context) {
+	format := c.DefaultQuery("format", bundle.FormatYAML)
+	if err := bundle.CheckFormat(format); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	b, err := h.bundleService.Export(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "application/yaml; charset=utf-8"
+	if format == bundle.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=rules."+format)
+	if err := bundle.Encode(c.Writer, b, format); err != nil {
+		c.Error(err)
+	}
+}
+
+// ImportRules 导入请求体中的规则包，Content-Type 为 application/json 时按JSON解析，否则按YAML解析
+// dry_run=true 时只返回与当前数据库的差异；reason 参数记录到审计日志
+func (h *BundleHandler) ImportRules(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	reason := strings.TrimSpace(c.Query("reason"))
+	if utf8.RuneCountInString(reason) > service.MaxReasonLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("变更原因不能超过%d个字符", service.MaxReasonLength)})
+		return
+	}
+
+	format := bundle.FormatYAML
+	if c.ContentType() == binding.MIMEJSON {
+		format = bundle.FormatJSON
+	}
+	b, err := bundle.Decode(c.Request.Body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	change := service.Change{
+		Actor:  c.GetString(gin.AuthUserKey),
+		Source: model.AuditSourceAPI,
+		Reason: reason,
+	}
+	result, err := h.bundleService.Import(c.Request.Context(), b, change, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidBundle) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler 提供域名属性缓存的统计信息
+type CatalogHandler struct {
+	catalog *service.AttributeCatalog
+}
+
+// NewCatalogHandler 创建一个新的CatalogHandler实例
+func NewCatalogHandler(catalog *service.AttributeCatalog) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog}
+}
+
+// GetStats 返回缓存的命中、未命中和加载次数
+func (h *CatalogHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.catalog.Stats())
+}
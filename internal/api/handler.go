@@ -1,11 +1,16 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"domainweb/internal/model"
 	"domainweb/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // Handler 处理HTTP请求
@@ -39,7 +44,7 @@ func (h *Handler) EstimateDomain(c *gin.Context) {
 		return
 	}
 
-	result, err := h.domainService.EstimateDomain(domain)
+	result, err := h.domainService.EstimateDomain(c.Request.Context(), domain)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": "估价失败: " + err.Error(),
@@ -48,7 +53,7 @@ func (h *Handler) EstimateDomain(c *gin.Context) {
 	}
 
 	// 保存查询历史
-	if err := h.historyService.SaveHistory(result); err != nil {
+	if err := h.historyService.SaveHistory(c.Request.Context(), result); err != nil {
 		// 仅记录错误，不影响用户体验
 		c.Error(err)
 	}
@@ -62,14 +67,13 @@ func (h *Handler) EstimateDomain(c *gin.Context) {
 // GetHistory 处理查询历史请求（Web界面）
 func (h *Handler) GetHistory(c *gin.Context) {
 	domain := c.Query("domain")
-	limitStr := c.DefaultQuery("limit", "50")
-
-	limit, err := strconv.Atoi(limitStr)
+	// 未指定或无效的limit交由HistoryService使用配置的默认值
+	limit, err := strconv.Atoi(c.Query("limit"))
 	if err != nil {
-		limit = 50
+		limit = 0
 	}
 
-	records, err := h.historyService.GetHistory(domain, limit)
+	records, err := h.historyService.GetHistory(c.Request.Context(), domain, limit)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": "获取历史记录失败: " + err.Error(),
@@ -95,14 +99,14 @@ func (h *Handler) APIEstimateDomain(c *gin.Context) {
 		return
 	}
 
-	result, err := h.domainService.EstimateDomain(request.Domain)
+	result, err := h.domainService.EstimateDomain(c.Request.Context(), request.Domain)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// 保存查询历史
-	if err := h.historyService.SaveHistory(result); err != nil {
+	if err := h.historyService.SaveHistory(c.Request.Context(), result); err != nil {
 		// 仅记录错误，不影响用户体验
 		c.Error(err)
 	}
@@ -110,17 +114,82 @@ func (h *Handler) APIEstimateDomain(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// APIEstimateBatch 处理批量估价请求（API）
+// 请求体可以是JSON {"domains": ["abc.com", ...]}，也可以是每行一个域名的纯文本
+func (h *Handler) APIEstimateBatch(c *gin.Context) {
+	domains, err := bindDomainList(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(domains) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "域名列表为空"})
+		return
+	}
+	if max := h.domainService.BatchMaxSize(); len(domains) > max {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("单次最多估价 %d 个域名", max)})
+		return
+	}
+
+	items, err := h.domainService.EstimateDomains(c.Request.Context(), domains)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 一次性保存所有成功结果的查询历史
+	results := make([]*model.EstimationResult, 0, len(items))
+	for _, item := range items {
+		if item.Result != nil {
+			results = append(results, item.Result)
+		}
+	}
+	if err := h.historyService.SaveHistories(c.Request.Context(), results); err != nil {
+		// 仅记录错误，不影响用户体验
+		c.Error(err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":     len(items),
+		"succeeded": len(results),
+		"failed":    len(items) - len(results),
+		"results":   items,
+	})
+}
+
+// bindDomainList 从请求体读取域名列表
+// 请求体可以是JSON {"domains": ["abc.com", ...]}，也可以是每行一个域名的纯文本
+func bindDomainList(c *gin.Context) ([]string, error) {
+	if c.ContentType() != binding.MIMEJSON {
+		return service.ReadDomainList(c.Request.Body)
+	}
+
+	var request struct {
+		Domains []string `json:"domains" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		return nil, errors.New("无效的请求参数")
+	}
+
+	var domains []string
+	for _, domain := range request.Domains {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, nil
+}
+
 // APIGetHistory 处理查询历史请求（API）
 func (h *Handler) APIGetHistory(c *gin.Context) {
 	domain := c.Query("domain")
-	limitStr := c.DefaultQuery("limit", "50")
-
-	limit, err := strconv.Atoi(limitStr)
+	// 未指定或无效的limit交由HistoryService使用配置的默认值
+	limit, err := strconv.Atoi(c.Query("limit"))
 	if err != nil {
-		limit = 50
+		limit = 0
 	}
 
-	records, err := h.historyService.GetHistory(domain, limit)
+	records, err := h.historyService.GetHistory(c.Request.Context(), domain, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
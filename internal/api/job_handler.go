@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"domainweb/internal/export"
+	"domainweb/internal/model"
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler 处理异步估价任务相关的HTTP请求
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+// NewJobHandler 创建一个新的JobHandler实例
+func NewJobHandler(jobService *service.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// CreateJob 提交异步估价任务，请求体格式与批量估价相同
+func (h *JobHandler) CreateJob(c *gin.Context) {
+	domains, err := bindDomainList(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(domains) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "域名列表为空"})
+		return
+	}
+	if max := h.jobService.MaxSize(); len(domains) > max {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("单个任务最多包含 %d 个域名", max)})
+		return
+	}
+
+	job, err := h.jobService.Submit(c.Request.Context(), domains)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetJob 查询任务状态和进度
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelJob 取消未结束的任务
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	job, err := h.jobService.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJobResults 下载已结束任务的估价结果，format 参数可选 json（默认）或 csv
+func (h *JobHandler) GetJobResults(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的格式: " + format})
+		return
+	}
+
+	job, items, err := h.jobService.Results(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"job": job, "results": items})
+		return
+	}
+
+	// 未估价的域名（任务被取消时）在CSV中以错误列说明
+	estimations := make([]model.BatchEstimationItem, len(items))
+	for i, item := range items {
+		estimations[i] = model.BatchEstimationItem{Domain: item.Domain, Result: item.Result, Error: item.Error}
+		if item.Status == model.JobItemPending {
+			estimations[i].Error = "未估价"
+		}
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="job-%s.csv"`, job.ID))
+	c.Status(http.StatusOK)
+	if err := export.WriteEstimationsCSV(c.Writer, estimations); err != nil {
+		c.Error(err)
+	}
+}
+
+// abortWithError 根据错误类型返回对应的状态码
+func (h *JobHandler) abortWithError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrJobNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrJobFinished), errors.Is(err, service.ErrJobNotFinished):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"domainweb/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RuleSetHandler 处理规则集版本和历史记录重新估价的HTTP请求
+type RuleSetHandler struct {
+	domainService  *service.DomainService
+	historyService *service.HistoryService
+	ruleSetService *service.RuleSetService
+}
+
+// NewRuleSetHandler 创建一个新的RuleSetHandler实例
+func NewRuleSetHandler(domainService *service.DomainService, historyService *service.HistoryService, ruleSetService *service.RuleSetService) *RuleSetHandler {
+	return &RuleSetHandler{
+		domainService:  domainService,
+		historyService: historyService,
+		ruleSetService: ruleSetService,
+	}
+}
+
+// ListRuleSets 列出最近的规则集版本，limit 默认为50
+func (h *RuleSetHandler) ListRuleSets(c *gin.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	ruleSets, err := h.ruleSetService.List(c.Request.Context(), limit)
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ruleSets)
+}
+
+// GetRuleSet 获取规则集版本及其完整内容
+func (h *RuleSetHandler) GetRuleSet(c *gin.Context) {
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的规则集版本"})
+		return
+	}
+
+	ruleSet, err := h.ruleSetService.Get(c.Request.Context(), version)
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ruleSet)
+}
+
+// RerunHistory 按规则集重新估价历史记录，against 参数可选 both（默认）、original 或 current
+func (h *RuleSetHandler) RerunHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的历史记录ID"})
+		return
+	}
+
+	against := c.DefaultQuery("against", service.RerunBoth)
+	switch against {
+	case service.RerunBoth, service.RerunOriginal, service.RerunCurrent:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "against 参数必须是 both、original 或 current"})
+		return
+	}
+
+	record, err := h.historyService.Get(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	result, err := h.domainService.Rerun(c.Request.Context(), record, against)
+	if err != nil {
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// abortWithError 根据错误类型返回对应的状态码
+func (h *RuleSetHandler) abortWithError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, service.ErrRuleSetNotFound) || errors.Is(err, service.ErrHistoryNotFound) {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
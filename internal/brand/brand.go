@@ -0,0 +1,153 @@
+// Package brand 按读音特征为字母域名计算0到100的可品牌化评分，用于评估 zovia 这类词典和拼音都无法识别的自造词
+//
+// 评分由四部分组成：辅音和元音交替的程度、按语料训练的字母二元组概率、适合品牌的长度，
+// 以及连续三个以上辅音或元音、q 后不接 u 等拗口组合的扣分。
+package brand
+
+import (
+	"math"
+	"strings"
+)
+
+// 各部分在评分中的权重，拗口组合每处扣除的分数
+const (
+	alternationWeight = 0.35
+	bigramWeight      = 0.45
+	lengthWeight      = 0.20
+	clusterPenalty    = 15
+)
+
+// 二元组平均对数概率映射到 [0, 1] 的区间，低于 minLogProb 为0，高于 maxLogProb 为1
+const (
+	minLogProb = -5.5
+	maxLogProb = -2.5
+)
+
+// boundary 表示单词的开头和结尾
+const boundary = '^'
+
+// Scorer 按训练得到的字母二元组概率计算评分
+type Scorer struct {
+	logProb map[[2]byte]float64 // 二元组的条件对数概率 log P(b|a)
+	unseen  map[byte]float64    // 未出现的二元组按加一平滑得到的对数概率
+}
+
+// NewScorer 用语料中的单词训练字母二元组模型，单词中的非字母字符被忽略
+func NewScorer(corpus []string) *Scorer {
+	counts := make(map[[2]byte]int)
+	totals := make(map[byte]int)
+	for _, word := range corpus {
+		prev := byte(boundary)
+		for _, c := range []byte(normalize(word) + string(boundary)) {
+			counts[[2]byte{prev, c}]++
+			totals[prev]++
+			prev = c
+		}
+	}
+
+	// 后继字符为26个字母加结尾
+	const symbols = 27
+	s := &Scorer{logProb: make(map[[2]byte]float64, len(counts)), unseen: make(map[byte]float64)}
+	for pair, n := range counts {
+		s.logProb[pair] = math.Log(float64(n+1) / float64(totals[pair[0]]+symbols))
+	}
+	for prev, total := range totals {
+		s.unseen[prev] = math.Log(1 / float64(total+symbols))
+	}
+	return s
+}
+
+// Score 返回 label 的可品牌化评分，label 不是纯字母时返回 false
+func (s *Scorer) Score(label string) (int, bool) {
+	label = strings.ToLower(label)
+	if label == "" || normalize(label) != label {
+		return 0, false
+	}
+
+	score := 100 * (alternationWeight*alternation(label) + bigramWeight*s.bigramScore(label) + lengthWeight*lengthScore(len(label)))
+	score -= float64(clusterPenalty * awkwardClusters(label))
+	return int(math.Round(math.Max(0, math.Min(100, score)))), true
+}
+
+// bigramScore 将包含开头和结尾的二元组平均对数概率映射到 [0, 1]
+func (s *Scorer) bigramScore(label string) float64 {
+	total, n := 0.0, 0
+	prev := byte(boundary)
+	for _, c := range []byte(label + string(boundary)) {
+		lp, ok := s.logProb[[2]byte{prev, c}]
+		if !ok {
+			lp, ok = s.unseen[prev]
+			if !ok {
+				lp = minLogProb * 2
+			}
+		}
+		total += lp
+		n++
+		prev = c
+	}
+	avg := total / float64(n)
+	return math.Max(0, math.Min(1, (avg-minLogProb)/(maxLogProb-minLogProb)))
+}
+
+// alternation 返回相邻字母中辅音和元音交替出现的比例，单个字母为1
+func alternation(label string) float64 {
+	if len(label) < 2 {
+		return 1
+	}
+	switched := 0
+	for i := 1; i < len(label); i++ {
+		if isVowel(label[i]) != isVowel(label[i-1]) {
+			switched++
+		}
+	}
+	return float64(switched) / float64(len(label)-1)
+}
+
+// lengthScore 返回长度的适合程度，4到8个字母为1，越短或越长越低
+func lengthScore(n int) float64 {
+	switch {
+	case n >= 4 && n <= 8:
+		return 1
+	case n < 4:
+		return float64(n) / 4
+	}
+	return math.Max(0, 1-float64(n-8)/8)
+}
+
+// awkwardClusters 统计拗口的组合：连续三个以上的辅音或元音，以及 q 后不接 u
+func awkwardClusters(label string) int {
+	count, run := 0, 1
+	for i := 1; i <= len(label); i++ {
+		if i < len(label) && isVowel(label[i]) == isVowel(label[i-1]) {
+			run++
+			continue
+		}
+		if run >= 3 {
+			count++
+		}
+		run = 1
+	}
+	for i := 0; i < len(label); i++ {
+		if label[i] == 'q' && (i+1 == len(label) || label[i+1] != 'u') {
+			count++
+		}
+	}
+	return count
+}
+
+// isVowel 判断字母是否为元音，y 也视为元音
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiouy", c) >= 0
+}
+
+// normalize 转换为小写并去掉非字母字符
+func normalize(word string) string {
+	var b strings.Builder
+	for i := 0; i < len(word); i++ {
+		c := word[i] | 0x20
+		if c >= 'a' && c <= 'z' {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package brand
+
+import (
+	"testing"
+
+	"domainweb/internal/dict"
+)
+
+// newTestScorer 用内置单词表训练评分模型，与 brandability 数据源一致
+func newTestScorer(t *testing.T) *Scorer {
+	t.Helper()
+	d, err := dict.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []string
+	for _, w := range d.Words() {
+		corpus = append(corpus, w.Text)
+	}
+	return NewScorer(corpus)
+}
+
+func TestScorePronounceable(t *testing.T) {
+	s := newTestScorer(t)
+
+	score := func(label string) int {
+		t.Helper()
+		n, ok := s.Score(label)
+		if !ok {
+			t.Fatalf("Score(%q) ok = false", label)
+		}
+		if n < 0 || n > 100 {
+			t.Fatalf("Score(%q) = %d, out of range", label, n)
+		}
+		return n
+	}
+
+	// 易读的自造词高于辅音堆砌的组合
+	for _, pair := range [][2]string{
+		{"zovia", "xkzrt"},
+		{"zovia", "bcdfgh"},
+		{"kalino", "strgth"},
+		{"zovia", "aeiouy"},
+	} {
+		if good, bad := score(pair[0]), score(pair[1]); good <= bad {
+			t.Errorf("Score(%s) = %d, want greater than Score(%s) = %d", pair[0], good, pair[1], bad)
+		}
+	}
+	if got := score("zovia"); got < 60 {
+		t.Errorf("Score(zovia) = %d, want at least 60", got)
+	}
+	if got := score("xkzrt"); got > 40 {
+		t.Errorf("Score(xkzrt) = %d, want at most 40", got)
+	}
+	// 不区分大小写
+	if score("Zovia") != score("zovia") {
+		t.Error("Score 区分了大小写")
+	}
+}
+
+func TestScoreQWithoutU(t *testing.T) {
+	s := newTestScorer(t)
+	qu, _ := s.Score("quzo")
+	qo, _ := s.Score("qozo")
+	if qo >= qu {
+		t.Errorf("Score(qozo) = %d, want less than Score(quzo) = %d", qo, qu)
+	}
+
+	tests := map[string]int{
+		"quzo":   0,
+		"qozo":   1,
+		"iraq":   1,
+		"qaqi":   2,
+		"strong": 1,
+		"beauty": 1,
+		"zovia":  0,
+	}
+	for label, want := range tests {
+		if got := awkwardClusters(label); got != want {
+			t.Errorf("awkwardClusters(%q) = %d, want %d", label, got, want)
+		}
+	}
+}
+
+func TestScoreRejectsNonLetters(t *testing.T) {
+	s := newTestScorer(t)
+	for _, label := range []string{"", "123", "zovia1", "zo-via", "中文", "xn--fiq228c"} {
+		if score, ok := s.Score(label); ok || score != 0 {
+			t.Errorf("Score(%q) = %d, %v, want 0, false", label, score, ok)
+		}
+	}
+}
+
+func TestLengthScore(t *testing.T) {
+	tests := map[int]float64{1: 0.25, 2: 0.5, 4: 1, 8: 1, 12: 0.5, 16: 0, 20: 0}
+	for n, want := range tests {
+		if got := lengthScore(n); got != want {
+			t.Errorf("lengthScore(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
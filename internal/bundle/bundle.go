@@ -0,0 +1,214 @@
+// Package bundle 定义规则包：域名属性和估价规则的可移植表示，用于在不同环境之间导出和导入
+//
+// 规则包是自描述的YAML或JSON文档，kind 和 version 字段标识格式。记录ID和时间戳与具体数据库相关，
+// 不包含在规则包中；导入时按内容与当前数据库中的记录匹配，得到新增、修改和删除的差异。
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"domainweb/internal/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 规则包的格式标识
+const (
+	Kind    = "domainweb/rules"
+	Version = 1
+)
+
+// 支持的编码格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Bundle 是一个规则包
+type Bundle struct {
+	Kind           string      `json:"kind" yaml:"kind"`
+	Version        int         `json:"version" yaml:"version"`
+	ExportedAt     time.Time   `json:"exportedAt,omitempty" yaml:"exportedAt,omitempty"`         // 导出时间
+	RuleSetVersion int64       `json:"ruleSetVersion,omitempty" yaml:"ruleSetVersion,omitempty"` // 导出时的规则集版本，仅供参考
+	Attributes     []Attribute `json:"attributes" yaml:"attributes"`
+	ValuationRules []Rule      `json:"valuationRules" yaml:"valuationRules"`
+}
+
+// Attribute 是规则包中的域名属性
+type Attribute struct {
+	Name        string   `json:"name" yaml:"name"`                             // 属性名称，如 com后缀
+	Type        string   `json:"type" yaml:"type"`                             // 属性类型，如 基础属性
+	Value       string   `json:"value" yaml:"value"`                           // 属性值，如 com
+	MinValue    *float64 `json:"minValue,omitempty" yaml:"minValue,omitempty"` // 取值范围的下限（含）
+	MaxValue    *float64 `json:"maxValue,omitempty" yaml:"maxValue,omitempty"` // 取值范围的上限（不含）
+	PriceFactor float64  `json:"priceFactor" yaml:"priceFactor"`               // 估价倍数
+	GradeFactor float64  `json:"gradeFactor" yaml:"gradeFactor"`               // 等级增量
+}
+
+// Rule 是规则包中的动态属性估价规则，省略 disabled 表示启用
+type Rule struct {
+	AttributeKey string   `json:"attributeKey" yaml:"attributeKey"`
+	Operator     string   `json:"operator" yaml:"operator"`
+	Value        string   `json:"value,omitempty" yaml:"value,omitempty"`
+	MinValue     *float64 `json:"minValue,omitempty" yaml:"minValue,omitempty"`
+	MaxValue     *float64 `json:"maxValue,omitempty" yaml:"maxValue,omitempty"`
+	Expression   string   `json:"expression,omitempty" yaml:"expression,omitempty"`
+	PriceFactor  float64  `json:"priceFactor" yaml:"priceFactor"`
+	GradeFactor  float64  `json:"gradeFactor" yaml:"gradeFactor"`
+	Label        string   `json:"label" yaml:"label"`
+	Description  string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Priority     int      `json:"priority" yaml:"priority"`
+	Disabled     bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// New 根据当前的域名属性和估价规则创建规则包
+func New(attrs []model.DomainAttribute, rules []model.ValuationRule) *Bundle {
+	b := &Bundle{
+		Kind:           Kind,
+		Version:        Version,
+		ExportedAt:     time.Now(),
+		Attributes:     make([]Attribute, len(attrs)),
+		ValuationRules: make([]Rule, len(rules)),
+	}
+	for i, attr := range attrs {
+		b.Attributes[i] = Attribute{
+			Name:        attr.AttributeName,
+			Type:        attr.AttributeType,
+			Value:       attr.AttributeValue,
+			MinValue:    attr.MinValue,
+			MaxValue:    attr.MaxValue,
+			PriceFactor: attr.PriceFactor,
+			GradeFactor: attr.GradeFactor,
+		}
+	}
+	for i, rule := range rules {
+		b.ValuationRules[i] = Rule{
+			AttributeKey: rule.AttributeKey,
+			Operator:     rule.Operator,
+			Value:        rule.Value,
+			MinValue:     rule.MinValue,
+			MaxValue:     rule.MaxValue,
+			Expression:   rule.Expression,
+			PriceFactor:  rule.PriceFactor,
+			GradeFactor:  rule.GradeFactor,
+			Label:        rule.Label,
+			Description:  rule.Description,
+			Priority:     rule.Priority,
+			Disabled:     !rule.Enabled,
+		}
+	}
+	return b
+}
+
+// DomainAttributes 返回规则包中的域名属性，ID为0
+func (b *Bundle) DomainAttributes() []model.DomainAttribute {
+	attrs := make([]model.DomainAttribute, len(b.Attributes))
+	for i, attr := range b.Attributes {
+		attrs[i] = model.DomainAttribute{
+			AttributeName:  attr.Name,
+			AttributeType:  attr.Type,
+			AttributeValue: attr.Value,
+			MinValue:       attr.MinValue,
+			MaxValue:       attr.MaxValue,
+			PriceFactor:    attr.PriceFactor,
+			GradeFactor:    attr.GradeFactor,
+		}
+	}
+	return attrs
+}
+
+// Rules 返回规则包中的估价规则，ID为0
+func (b *Bundle) Rules() []model.ValuationRule {
+	rules := make([]model.ValuationRule, len(b.ValuationRules))
+	for i, rule := range b.ValuationRules {
+		rules[i] = model.ValuationRule{
+			AttributeKey: rule.AttributeKey,
+			Operator:     rule.Operator,
+			Value:        rule.Value,
+			MinValue:     rule.MinValue,
+			MaxValue:     rule.MaxValue,
+			Expression:   rule.Expression,
+			PriceFactor:  rule.PriceFactor,
+			GradeFactor:  rule.GradeFactor,
+			Label:        rule.Label,
+			Description:  rule.Description,
+			Priority:     rule.Priority,
+			Enabled:      !rule.Disabled,
+		}
+	}
+	return rules
+}
+
+// FormatFromPath 根据文件扩展名判断编码格式，无法判断时返回 YAML
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// CheckFormat 校验编码格式
+func CheckFormat(format string) error {
+	if format != FormatYAML && format != FormatJSON {
+		return fmt.Errorf("不支持的规则包格式: %s（可选：yaml、json）", format)
+	}
+	return nil
+}
+
+// Encode 以指定格式输出规则包
+func Encode(w io.Writer, b *Bundle, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(b)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(b); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	return CheckFormat(format)
+}
+
+// Decode 读取指定格式的规则包，拒绝未知字段以及 kind 或 version 不匹配的文档
+func Decode(r io.Reader, format string) (*Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取规则包失败: %w", err)
+	}
+
+	var b Bundle
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&b)
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&b)
+	default:
+		return nil, CheckFormat(format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析规则包失败: %w", err)
+	}
+
+	if b.Kind != Kind {
+		return nil, fmt.Errorf("不是规则包: kind 应为 %s，实际为 %q", Kind, b.Kind)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("不支持的规则包版本 %d，当前支持版本 %d", b.Version, Version)
+	}
+	return &b, nil
+}
@@ -0,0 +1,65 @@
+package bundle
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"domainweb/internal/model"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	lower := 4.0
+	rule := valuationRule(7, "alexa_rank", "1000", "排名顶尖", 10)
+	rule.Enabled = false
+	attrs := []model.DomainAttribute{{ID: 3, AttributeName: "4-6位长度", AttributeType: model.AttributeTypeBase, MinValue: &lower, PriceFactor: 2}}
+	b := New(attrs, []model.ValuationRule{rule})
+
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		var buf bytes.Buffer
+		if err := Encode(&buf, b, format); err != nil {
+			t.Fatalf("Encode(%s) error = %v", format, err)
+		}
+		decoded, err := Decode(&buf, format)
+		if err != nil {
+			t.Fatalf("Decode(%s) error = %v", format, err)
+		}
+
+		// 规则包不包含记录ID
+		wantAttrs := []model.DomainAttribute{attrs[0]}
+		wantAttrs[0].ID = 0
+		if got := decoded.DomainAttributes(); !reflect.DeepEqual(got, wantAttrs) {
+			t.Errorf("%s: DomainAttributes() = %+v", format, got)
+		}
+		wantRule := rule
+		wantRule.ID = 0
+		if got := decoded.Rules(); !reflect.DeepEqual(got, []model.ValuationRule{wantRule}) {
+			t.Errorf("%s: Rules() = %+v", format, got)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		want   string
+	}{
+		{FormatYAML, "kind: other\nversion: 1\n", "不是规则包"},
+		{FormatYAML, "kind: domainweb/rules\nversion: 2\n", "不支持的规则包版本 2"},
+		{FormatYAML, "kind: domainweb/rules\nversion: 1\nunknown: 1\n", "解析规则包失败"},
+		{FormatJSON, `{"kind": "domainweb/rules", "version": 1, "extra": true}`, "解析规则包失败"},
+		{"xml", "", "不支持的规则包格式"},
+	}
+	for _, tt := range tests {
+		_, err := Decode(strings.NewReader(tt.data), tt.format)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Decode(%q) error = %v, want error containing %q", tt.data, err, tt.want)
+		}
+	}
+
+	if FormatFromPath("rules.JSON") != FormatJSON || FormatFromPath("rules.yml") != FormatYAML {
+		t.Error("FormatFromPath() 判断错误")
+	}
+}
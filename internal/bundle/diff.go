@@ -0,0 +1,232 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"domainweb/internal/model"
+)
+
+// 差异中的操作类型，与审计日志一致
+const (
+	ActionCreate = model.AuditActionCreate
+	ActionUpdate = model.AuditActionUpdate
+	ActionDelete = model.AuditActionDelete
+)
+
+// FieldChange 表示一个字段修改前后的值
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// AttributeChange 表示一个域名属性的变更，新增时 Before 为空，删除时 After 为空
+type AttributeChange struct {
+	Action string                 `json:"action"`
+	Before *model.DomainAttribute `json:"before,omitempty"`
+	After  *model.DomainAttribute `json:"after,omitempty"`
+	Fields []FieldChange          `json:"fields,omitempty"` // 修改的字段
+}
+
+// RuleChange 表示一条估价规则的变更，新增时 Before 为空，删除时 After 为空
+type RuleChange struct {
+	Action string               `json:"action"`
+	Before *model.ValuationRule `json:"before,omitempty"`
+	After  *model.ValuationRule `json:"after,omitempty"`
+	Fields []FieldChange        `json:"fields,omitempty"` // 修改的字段
+}
+
+// Diff 表示将规则包应用到当前数据库所需的变更
+type Diff struct {
+	Attributes     []AttributeChange `json:"attributes"`
+	ValuationRules []RuleChange      `json:"valuationRules"`
+}
+
+// Summary 统计各类变更的数量
+type Summary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Compare 比较当前的域名属性、估价规则和导入后的目标内容（通常来自 Bundle.DomainAttributes 和 Bundle.Rules）
+// 域名属性按类型、名称和属性值匹配，估价规则按属性键、运算符和条件匹配，匹配上但其他字段不同的记为修改；
+// 同一键有多条记录时按顺序一一对应。目标中没有的记录将被删除。
+func Compare(attrs []model.DomainAttribute, rules []model.ValuationRule, targetAttrs []model.DomainAttribute, targetRules []model.ValuationRule) *Diff {
+	diff := &Diff{Attributes: []AttributeChange{}, ValuationRules: []RuleChange{}}
+
+	current := make(map[string][]model.DomainAttribute)
+	for _, attr := range attrs {
+		key := attributeKey(attr)
+		current[key] = append(current[key], attr)
+	}
+	for _, attr := range targetAttrs {
+		attr := attr
+		key := attributeKey(attr)
+		matches := current[key]
+		if len(matches) == 0 {
+			diff.Attributes = append(diff.Attributes, AttributeChange{Action: ActionCreate, After: &attr})
+			continue
+		}
+		before := matches[0]
+		current[key] = matches[1:]
+		attr.ID = before.ID
+		if !attr.Equal(before) {
+			diff.Attributes = append(diff.Attributes, AttributeChange{
+				Action: ActionUpdate, Before: &before, After: &attr, Fields: compareFields(before, attr),
+			})
+		}
+	}
+	for _, attr := range attrs {
+		attr := attr
+		for _, remaining := range current[attributeKey(attr)] {
+			if remaining.ID == attr.ID {
+				diff.Attributes = append(diff.Attributes, AttributeChange{Action: ActionDelete, Before: &attr})
+			}
+		}
+	}
+
+	currentRules := make(map[string][]model.ValuationRule)
+	for _, rule := range rules {
+		key := ruleKey(rule)
+		currentRules[key] = append(currentRules[key], rule)
+	}
+	for _, rule := range targetRules {
+		rule := rule
+		key := ruleKey(rule)
+		matches := currentRules[key]
+		if len(matches) == 0 {
+			diff.ValuationRules = append(diff.ValuationRules, RuleChange{Action: ActionCreate, After: &rule})
+			continue
+		}
+		before := matches[0]
+		currentRules[key] = matches[1:]
+		rule.ID, rule.CreatedAt, rule.UpdatedAt = before.ID, before.CreatedAt, before.UpdatedAt
+		if fields := compareFields(before, rule); len(fields) > 0 {
+			diff.ValuationRules = append(diff.ValuationRules, RuleChange{
+				Action: ActionUpdate, Before: &before, After: &rule, Fields: fields,
+			})
+		}
+	}
+	for _, rule := range rules {
+		rule := rule
+		for _, remaining := range currentRules[ruleKey(rule)] {
+			if remaining.ID == rule.ID {
+				diff.ValuationRules = append(diff.ValuationRules, RuleChange{Action: ActionDelete, Before: &rule})
+			}
+		}
+	}
+	return diff
+}
+
+// Empty 判断是否没有任何变更
+func (d *Diff) Empty() bool {
+	return len(d.Attributes) == 0 && len(d.ValuationRules) == 0
+}
+
+// AttributeSummary 统计域名属性的变更数量，total 为目标中的属性数量
+func (d *Diff) AttributeSummary(total int) Summary {
+	var s Summary
+	for _, c := range d.Attributes {
+		s.add(c.Action)
+	}
+	s.Unchanged = total - s.Created - s.Updated
+	return s
+}
+
+// RuleSummary 统计估价规则的变更数量，total 为目标中的规则数量
+func (d *Diff) RuleSummary(total int) Summary {
+	var s Summary
+	for _, c := range d.ValuationRules {
+		s.add(c.Action)
+	}
+	s.Unchanged = total - s.Created - s.Updated
+	return s
+}
+
+// add 按操作类型累加计数
+func (s *Summary) add(action string) {
+	switch action {
+	case ActionCreate:
+		s.Created++
+	case ActionUpdate:
+		s.Updated++
+	case ActionDelete:
+		s.Deleted++
+	}
+}
+
+// Changes 将差异转换为可以在一个事务中应用的变更
+func (d *Diff) Changes() *model.RuleChanges {
+	changes := &model.RuleChanges{}
+	for _, c := range d.Attributes {
+		switch c.Action {
+		case ActionCreate:
+			changes.CreateAttributes = append(changes.CreateAttributes, *c.After)
+		case ActionUpdate:
+			changes.UpdateAttributes = append(changes.UpdateAttributes, *c.After)
+		case ActionDelete:
+			changes.DeleteAttributes = append(changes.DeleteAttributes, c.Before.ID)
+		}
+	}
+	for _, c := range d.ValuationRules {
+		switch c.Action {
+		case ActionCreate:
+			changes.CreateRules = append(changes.CreateRules, *c.After)
+		case ActionUpdate:
+			changes.UpdateRules = append(changes.UpdateRules, *c.After)
+		case ActionDelete:
+			changes.DeleteRules = append(changes.DeleteRules, c.Before.ID)
+		}
+	}
+	return changes
+}
+
+// attributeKey 返回匹配域名属性使用的键
+func attributeKey(attr model.DomainAttribute) string {
+	return attr.AttributeType + "\x00" + attr.AttributeName + "\x00" + attr.AttributeValue
+}
+
+// ruleKey 返回匹配估价规则使用的键，由属性键、运算符和条件组成
+func ruleKey(rule model.ValuationRule) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s\x00%s", rule.AttributeKey, rule.Operator, rule.Value,
+		optionalFloat(rule.MinValue), optionalFloat(rule.MaxValue), rule.Expression)
+}
+
+// optionalFloat 将可选的数值转换为文本，为空时返回空字符串
+func optionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
+
+// compareFields 按JSON字段比较两个值，返回不同的字段，按字段名排序
+func compareFields(before, after interface{}) []FieldChange {
+	var b, a map[string]json.RawMessage
+	beforeData, _ := json.Marshal(before)
+	afterData, _ := json.Marshal(after)
+	json.Unmarshal(beforeData, &b)
+	json.Unmarshal(afterData, &a)
+
+	var fields []FieldChange
+	for field, value := range a {
+		if string(b[field]) != string(value) {
+			fields = append(fields, FieldChange{Field: field, Before: fieldString(b[field]), After: fieldString(value)})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields
+}
+
+// fieldString 将JSON值转换为便于阅读的文本，字符串去掉引号
+func fieldString(value json.RawMessage) string {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		return s
+	}
+	return string(value)
+}
@@ -0,0 +1,121 @@
+package bundle
+
+import (
+	"reflect"
+	"testing"
+
+	"domainweb/internal/model"
+)
+
+func attribute(id int64, name, value string, price float64) model.DomainAttribute {
+	return model.DomainAttribute{ID: id, AttributeName: name, AttributeType: model.AttributeTypeBase, AttributeValue: value, PriceFactor: price}
+}
+
+func valuationRule(id int64, key, value, label string, priority int) model.ValuationRule {
+	return model.ValuationRule{ID: id, AttributeKey: key, Operator: "<", Value: value, PriceFactor: 1, Label: label, Priority: priority, Enabled: true}
+}
+
+func TestCompare(t *testing.T) {
+	attrs := []model.DomainAttribute{
+		attribute(1, "com后缀", "com", 9.55),
+		attribute(2, "net后缀", "net", 3),
+		attribute(3, "org后缀", "org", 2),
+	}
+	rules := []model.ValuationRule{
+		valuationRule(1, "alexa_rank", "1000", "排名顶尖", 10),
+		valuationRule(2, "alexa_rank", "100000", "排名一般", 20),
+		valuationRule(3, "search_volume", "10", "搜索量低", 30),
+	}
+
+	targetAttrs := []model.DomainAttribute{
+		attribute(0, "com后缀", "com", 10),
+		attribute(0, "org后缀", "org", 2),
+		attribute(0, "cn后缀", "cn", 4),
+	}
+	targetRules := []model.ValuationRule{
+		valuationRule(0, "alexa_rank", "1000", "排名很高", 10),
+		valuationRule(0, "alexa_rank", "100000", "排名一般", 20),
+		valuationRule(0, "baike_index", "50", "百科较少", 40),
+	}
+
+	diff := Compare(attrs, rules, targetAttrs, targetRules)
+
+	var actions []string
+	for _, c := range diff.Attributes {
+		name := ""
+		if c.After != nil {
+			name = c.After.AttributeName
+		} else {
+			name = c.Before.AttributeName
+		}
+		actions = append(actions, c.Action+" "+name)
+	}
+	if want := []string{"update com后缀", "create cn后缀", "delete net后缀"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("属性差异 = %v, want %v", actions, want)
+	}
+	update := diff.Attributes[0]
+	if update.After.ID != 1 || update.Before.PriceFactor != 9.55 ||
+		!reflect.DeepEqual(update.Fields, []FieldChange{{Field: "priceFactor", Before: "9.55", After: "10"}}) {
+		t.Errorf("属性修改 = %+v", update)
+	}
+
+	actions = nil
+	for _, c := range diff.ValuationRules {
+		if c.After != nil {
+			actions = append(actions, c.Action+" "+c.After.Label)
+		} else {
+			actions = append(actions, c.Action+" "+c.Before.Label)
+		}
+	}
+	if want := []string{"update 排名很高", "create 百科较少", "delete 搜索量低"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("规则差异 = %v, want %v", actions, want)
+	}
+	if fields := diff.ValuationRules[0].Fields; !reflect.DeepEqual(fields, []FieldChange{{Field: "label", Before: "排名顶尖", After: "排名很高"}}) {
+		t.Errorf("规则修改的字段 = %+v", fields)
+	}
+
+	if got, want := diff.AttributeSummary(len(targetAttrs)), (Summary{Created: 1, Updated: 1, Deleted: 1, Unchanged: 1}); got != want {
+		t.Errorf("AttributeSummary() = %+v, want %+v", got, want)
+	}
+	if got, want := diff.RuleSummary(len(targetRules)), (Summary{Created: 1, Updated: 1, Deleted: 1, Unchanged: 1}); got != want {
+		t.Errorf("RuleSummary() = %+v, want %+v", got, want)
+	}
+
+	changes := diff.Changes()
+	if len(changes.CreateAttributes) != 1 || changes.CreateAttributes[0].AttributeValue != "cn" ||
+		len(changes.UpdateAttributes) != 1 || changes.UpdateAttributes[0].ID != 1 ||
+		!reflect.DeepEqual(changes.DeleteAttributes, []int64{2}) {
+		t.Errorf("属性变更 = %+v", changes)
+	}
+	if len(changes.CreateRules) != 1 || len(changes.UpdateRules) != 1 || changes.UpdateRules[0].ID != 1 ||
+		!reflect.DeepEqual(changes.DeleteRules, []int64{3}) {
+		t.Errorf("规则变更 = %+v", changes)
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	attrs := []model.DomainAttribute{attribute(1, "com后缀", "com", 9.55)}
+	rules := []model.ValuationRule{valuationRule(1, "alexa_rank", "1000", "排名顶尖", 10)}
+
+	// 导出再导入同样的内容没有差异
+	b := New(attrs, rules)
+	diff := Compare(attrs, rules, b.DomainAttributes(), b.Rules())
+	if !diff.Empty() {
+		t.Errorf("Compare() = %+v, want empty", diff)
+	}
+	if got := diff.AttributeSummary(1); got != (Summary{Unchanged: 1}) {
+		t.Errorf("AttributeSummary() = %+v", got)
+	}
+}
+
+func TestCompareDuplicates(t *testing.T) {
+	// 同一键有多条记录时按顺序一一对应，多出的记录被删除
+	attrs := []model.DomainAttribute{
+		attribute(1, "com后缀", "com", 9.55),
+		attribute(2, "com后缀", "com", 1),
+	}
+	diff := Compare(attrs, nil, []model.DomainAttribute{attribute(0, "com后缀", "com", 9.55)}, nil)
+	if len(diff.Attributes) != 1 || diff.Attributes[0].Action != ActionDelete || diff.Attributes[0].Before.ID != 2 {
+		t.Errorf("Compare() = %+v", diff.Attributes)
+	}
+}
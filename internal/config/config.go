@@ -0,0 +1,494 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// DefaultPath 是默认的配置文件路径
+const DefaultPath = "config/config.json"
+
+// 动态属性模拟数据的取值模式
+const (
+	MockModeRandom        = "random"        // 随机模式：模拟数据随时间变化
+	MockModeDeterministic = "deterministic" // 确定性模式：模拟数据由域名和种子决定
+)
+
+// LiveProviders 列出查询实时注册数据的动态属性数据源，确定性模式下不能启用
+var LiveProviders = []string{"rdap", "whois"}
+
+// EnvPrefix 是环境变量覆盖配置时使用的前缀，如 DOMAINWEB_SERVER_PORT
+const EnvPrefix = "DOMAINWEB_"
+
+// Config 表示应用程序的全部配置
+type Config struct {
+	Server     ServerConfig     `json:"server"`
+	Database   DatabaseConfig   `json:"database"`
+	Estimation EstimationConfig `json:"estimation"`
+	Jobs       JobsConfig       `json:"jobs"`
+	Domain     DomainConfig     `json:"domain"`
+	Dynamic    DynamicConfig    `json:"dynamic"`
+	Admin      AdminConfig      `json:"admin"`
+}
+
+// ServerConfig 表示HTTP服务器配置
+type ServerConfig struct {
+	Port           int    `json:"port"`           // 监听端口
+	Host           string `json:"host"`           // 监听地址
+	ReadTimeout    int    `json:"readTimeout"`    // 读取超时（秒）
+	WriteTimeout   int    `json:"writeTimeout"`   // 写入超时（秒）
+	MaxHeaderBytes int    `json:"maxHeaderBytes"` // 最大请求头大小
+}
+
+// DatabaseConfig 表示数据库连接配置
+type DatabaseConfig struct {
+	Driver          string `json:"driver"`          // 数据库驱动：mysql 或 sqlite
+	Path            string `json:"path"`            // SQLite数据库文件路径，":memory:" 表示内存数据库
+	Host            string `json:"host"`            // 数据库地址
+	Port            int    `json:"port"`            // 数据库端口
+	Username        string `json:"username"`        // 用户名
+	Password        string `json:"password"`        // 密码
+	DBName          string `json:"dbname"`          // 数据库名称
+	Charset         string `json:"charset"`         // 字符集
+	ParseTime       bool   `json:"parseTime"`       // 是否解析时间类型
+	MaxOpenConns    int    `json:"maxOpenConns"`    // 最大打开连接数
+	MaxIdleConns    int    `json:"maxIdleConns"`    // 最大空闲连接数
+	ConnMaxLifetime int    `json:"connMaxLifetime"` // 连接最大存活时间（秒）
+	AutoMigrate     bool   `json:"autoMigrate"`     // 启动服务时是否自动应用未执行的迁移
+}
+
+// EstimationConfig 表示估价相关配置
+type EstimationConfig struct {
+	BasePrice           float64 `json:"basePrice"`           // 估价基数（元）
+	BaseGrade           float64 `json:"baseGrade"`           // 等级基数
+	DefaultHistoryLimit int     `json:"defaultHistoryLimit"` // 默认历史记录条数
+	BatchConcurrency    int     `json:"batchConcurrency"`    // 批量估价的并发数
+	BatchMaxSize        int     `json:"batchMaxSize"`        // 单次批量估价的最大域名数量
+	AttributeRefresh    int     `json:"attributeRefresh"`    // 域名属性和估价规则缓存的刷新间隔（秒），0表示只在修改后刷新
+}
+
+// JobsConfig 表示异步估价任务相关配置
+type JobsConfig struct {
+	MaxRunning int `json:"maxRunning"` // 同时执行的任务数量
+	ChunkSize  int `json:"chunkSize"`  // 每次估价并保存进度的域名数量
+	MaxSize    int `json:"maxSize"`    // 单个任务的最大域名数量
+}
+
+// DomainConfig 表示域名解析相关配置
+type DomainConfig struct {
+	PublicSuffixFile       string `json:"publicSuffixFile"`       // 公共后缀列表文件，为空时使用内置列表
+	IncludePrivateSuffixes bool   `json:"includePrivateSuffixes"` // 是否包含私有域后缀（如 github.io）
+}
+
+// DynamicConfig 表示动态属性获取相关配置
+type DynamicConfig struct {
+	CacheTTL  int             `json:"cacheTTL"`  // 动态属性缓存有效期（秒）
+	Timeout   int             `json:"timeout"`   // 单个数据源的获取超时（秒），0表示不限制
+	Providers map[string]bool `json:"providers"` // 数据源启用状态，未列出的数据源保持默认启用
+	MockMode  string          `json:"mockMode"`  // 模拟数据模式：random 或 deterministic
+	MockSeed  int64           `json:"mockSeed"`  // 确定性模式的种子
+	Whois     WhoisConfig     `json:"whois"`
+	RDAP      RDAPConfig      `json:"rdap"`
+}
+
+// WhoisConfig 表示WHOIS查询相关配置
+type WhoisConfig struct {
+	Timeout      int               `json:"timeout"`      // 单次查询超时（秒）
+	MaxReferrals int               `json:"maxReferrals"` // 最多跟随的注册商转介次数
+	Discovery    bool              `json:"discovery"`    // 是否通过whois.iana.org发现未知顶级域的服务器
+	Servers      map[string]string `json:"servers"`      // 覆盖内置的顶级域WHOIS服务器，如 {"com": "whois.example.com:43"}
+}
+
+// RDAPConfig 表示RDAP查询相关配置
+type RDAPConfig struct {
+	Timeout         int               `json:"timeout"`         // 单次查询超时（秒）
+	BootstrapFile   string            `json:"bootstrapFile"`   // IANA引导文件（dns.json）路径，为空时使用内置文件
+	FallbackToWhois bool              `json:"fallbackToWhois"` // RDAP不可用时是否回退到WHOIS
+	Servers         map[string]string `json:"servers"`         // 覆盖引导文件中的服务地址，如 {"com": "https://rdap.example.com/"}
+}
+
+// AdminConfig 表示管理后台配置，用户名或密码为空时不启用管理后台
+type AdminConfig struct {
+	Username string `json:"username"` // 管理员用户名
+	Password string `json:"password"` // 管理员密码，建议通过环境变量 DOMAINWEB_ADMIN_PASSWORD 设置
+}
+
+// Default 返回带有默认值的配置
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:           8080,
+			Host:           "0.0.0.0",
+			ReadTimeout:    10,
+			WriteTimeout:   10,
+			MaxHeaderBytes: 1 << 20,
+		},
+		Database: DatabaseConfig{
+			Driver:          "mysql",
+			Path:            "domainweb.db",
+			Host:            "127.0.0.1",
+			Port:            3306,
+			DBName:          "domainweb",
+			Charset:         "utf8mb4",
+			ParseTime:       true,
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 3600,
+		},
+		Estimation: EstimationConfig{
+			BasePrice:           25.0,
+			BaseGrade:           -0.5,
+			DefaultHistoryLimit: 50,
+			BatchConcurrency:    8,
+			BatchMaxSize:        5000,
+			AttributeRefresh:    60,
+		},
+		Jobs: JobsConfig{
+			MaxRunning: 2,
+			ChunkSize:  100,
+			MaxSize:    100000,
+		},
+		Dynamic: DynamicConfig{
+			CacheTTL: 86400,
+			Timeout:  10,
+			MockMode: MockModeRandom,
+			Whois: WhoisConfig{
+				Timeout:      8,
+				MaxReferrals: 2,
+				Discovery:    true,
+			},
+			RDAP: RDAPConfig{
+				Timeout:         8,
+				FallbackToWhois: true,
+			},
+		},
+	}
+}
+
+// Load 依次加载默认值、配置文件和环境变量
+// path 为空时使用默认路径；默认路径的文件不存在时仅使用默认值
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		// 默认配置文件不存在，继续使用默认值
+	default:
+		return nil, fmt.Errorf("读取配置文件 %s 失败: %w", path, err)
+	}
+
+	if err := cfg.ApplyEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// ApplyEnv 使用 DOMAINWEB_<节>_<字段> 形式的环境变量覆盖配置，嵌套的配置项依次写出各级名称
+// 例如 DOMAINWEB_SERVER_PORT=9090、DOMAINWEB_DATABASE_PASSWORD=secret、
+// DOMAINWEB_DYNAMIC_RDAP_TIMEOUT=5、DOMAINWEB_DYNAMIC_PROVIDERS_MOCK_WHOIS=false
+// 不对应任何配置项的环境变量只记录警告，不视为错误
+func (c *Config) ApplyEnv(environ []string) error {
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, EnvPrefix) {
+			continue
+		}
+		idx := strings.Index(kv, "=")
+		if idx == -1 {
+			continue
+		}
+
+		name := strings.TrimPrefix(kv[:idx], EnvPrefix)
+		path, ok := envPath(reflect.ValueOf(c).Elem(), strings.Split(name, "_"))
+		if !ok {
+			log.Printf("忽略未知的配置环境变量: %s", kv[:idx])
+			continue
+		}
+		if err := c.Set(strings.Join(path, "."), kv[idx+1:]); err != nil {
+			return fmt.Errorf("环境变量 %s 无效: %w", kv[:idx], err)
+		}
+	}
+	return nil
+}
+
+// envPath 将环境变量名按下划线拆分后的各段解析为配置键的路径
+// 字段名可能包含多段（如 MAX_OPEN_CONNS），优先匹配最长的字段名；映射的键为剩余各段以下划线连接的小写形式
+func envPath(v reflect.Value, tokens []string) ([]string, bool) {
+	if len(tokens) == 0 {
+		return nil, false
+	}
+	if v.Kind() == reflect.Map {
+		return []string{strings.ToLower(strings.Join(tokens, "_"))}, true
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := len(tokens); i > 0; i-- {
+		name := strings.Join(tokens[:i], "")
+		field, ok := fieldByTag(v, name)
+		if !ok {
+			continue
+		}
+		if i == len(tokens) {
+			if field.Kind() == reflect.Struct || field.Kind() == reflect.Map {
+				return nil, false
+			}
+			return []string{name}, true
+		}
+		if rest, ok := envPath(field, tokens[i:]); ok {
+			return append([]string{name}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// Set 按 "节.字段" 形式的键设置配置值，键不区分大小写，如 "server.port"
+// 嵌套的配置项和映射的元素依次写出各级名称，如 "dynamic.rdap.timeout"、"dynamic.providers.whois"
+func (c *Config) Set(key, value string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("无效的配置键: %s", key)
+	}
+
+	v := reflect.ValueOf(c).Elem()
+	for i, part := range parts {
+		if v.Kind() == reflect.Map {
+			// 映射的键可能包含点号，如 "dynamic.rdap.servers.co.uk"
+			return setMapValue(v, strings.ToLower(strings.Join(parts[i:], ".")), value)
+		}
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("未知的配置项: %s", key)
+		}
+		field, ok := fieldByTag(v, part)
+		if !ok {
+			if i == 0 {
+				return fmt.Errorf("未知的配置节: %s", part)
+			}
+			return fmt.Errorf("未知的配置项: %s", key)
+		}
+		v = field
+	}
+
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+		return fmt.Errorf("配置项 %s 不能直接设置", key)
+	}
+	return setValue(v, value)
+}
+
+// setMapValue 将字符串解析为映射元素的类型后写入映射，映射为空时先创建
+func setMapValue(m reflect.Value, key, value string) error {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	elem := reflect.New(m.Type().Elem()).Elem()
+	if err := setValue(elem, value); err != nil {
+		return err
+	}
+	m.SetMapIndex(reflect.ValueOf(key), elem)
+	return nil
+}
+
+// fieldByTag 按json标签（忽略大小写和下划线）查找结构体字段
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	name = normalizeKey(name)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if normalizeKey(tag) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+// setValue 将字符串解析为字段对应的类型并赋值
+func setValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("需要整数: %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("需要数字: %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("需要布尔值: %q", value)
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("不支持的配置类型: %s", field.Kind())
+	}
+	return nil
+}
+
+// Validate 校验配置是否合法
+func (c *Config) Validate() error {
+	var errs []string
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("server.port 超出范围: %d", c.Server.Port))
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 {
+		errs = append(errs, "server 超时时间不能为负数")
+	}
+	if c.Server.MaxHeaderBytes < 0 {
+		errs = append(errs, "server.maxHeaderBytes 不能为负数")
+	}
+
+	switch c.Database.Driver {
+	case "mysql":
+		if c.Database.Host == "" {
+			errs = append(errs, "database.host 不能为空")
+		}
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			errs = append(errs, fmt.Sprintf("database.port 超出范围: %d", c.Database.Port))
+		}
+		if c.Database.DBName == "" {
+			errs = append(errs, "database.dbname 不能为空")
+		}
+	case "sqlite", "sqlite3":
+		if c.Database.Path == "" {
+			errs = append(errs, "使用sqlite时 database.path 不能为空")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("不支持的数据库驱动: %s", c.Database.Driver))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 || c.Database.ConnMaxLifetime < 0 {
+		errs = append(errs, "database 连接池参数不能为负数")
+	}
+
+	if c.Estimation.BasePrice <= 0 {
+		errs = append(errs, "estimation.basePrice 必须大于0")
+	}
+	if c.Estimation.DefaultHistoryLimit <= 0 {
+		errs = append(errs, "estimation.defaultHistoryLimit 必须大于0")
+	}
+	if c.Estimation.BatchConcurrency <= 0 || c.Estimation.BatchMaxSize <= 0 {
+		errs = append(errs, "estimation.batchConcurrency 和 estimation.batchMaxSize 必须大于0")
+	}
+	if c.Estimation.AttributeRefresh < 0 {
+		errs = append(errs, "estimation.attributeRefresh 不能为负数")
+	}
+	if c.Jobs.MaxRunning <= 0 || c.Jobs.ChunkSize <= 0 || c.Jobs.MaxSize <= 0 {
+		errs = append(errs, "jobs.maxRunning、jobs.chunkSize 和 jobs.maxSize 必须大于0")
+	}
+
+	if c.Dynamic.CacheTTL < 0 || c.Dynamic.Timeout < 0 {
+		errs = append(errs, "dynamic 缓存有效期和超时时间不能为负数")
+	}
+	if c.Dynamic.MockMode != MockModeRandom && c.Dynamic.MockMode != MockModeDeterministic {
+		errs = append(errs, fmt.Sprintf("不支持的模拟数据模式: %s", c.Dynamic.MockMode))
+	}
+	if c.Dynamic.MockMode == MockModeDeterministic {
+		for _, name := range LiveProviders {
+			if c.Dynamic.Providers[name] {
+				errs = append(errs, fmt.Sprintf("确定性模拟模式下不能启用实时数据源: dynamic.providers.%s", name))
+			}
+		}
+	}
+	if c.Dynamic.Whois.Timeout < 0 || c.Dynamic.Whois.MaxReferrals < 0 {
+		errs = append(errs, "dynamic.whois 超时时间和转介次数不能为负数")
+	}
+	if c.Dynamic.RDAP.Timeout < 0 {
+		errs = append(errs, "dynamic.rdap.timeout 不能为负数")
+	}
+
+	if (c.Admin.Username == "") != (c.Admin.Password == "") {
+		errs = append(errs, "admin.username 和 admin.password 必须同时设置")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("配置无效: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Enabled 判断是否启用管理后台
+func (a AdminConfig) Enabled() bool {
+	return a.Username != "" && a.Password != ""
+}
+
+// Addr 返回服务器监听地址
+func (s ServerConfig) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// ReadTimeoutDuration 返回读取超时时间
+func (s ServerConfig) ReadTimeoutDuration() time.Duration {
+	return time.Duration(s.ReadTimeout) * time.Second
+}
+
+// WriteTimeoutDuration 返回写入超时时间
+func (s ServerConfig) WriteTimeoutDuration() time.Duration {
+	return time.Duration(s.WriteTimeout) * time.Second
+}
+
+// DSN 返回MySQL数据源名称
+func (d DatabaseConfig) DSN() string {
+	mc := mysql.NewConfig()
+	mc.User = d.Username
+	mc.Passwd = d.Password
+	mc.Net = "tcp"
+	mc.Addr = net.JoinHostPort(d.Host, strconv.Itoa(d.Port))
+	mc.DBName = d.DBName
+	mc.ParseTime = d.ParseTime
+	mc.Loc = time.Local
+	if d.Charset != "" {
+		mc.Params = map[string]string{"charset": d.Charset}
+	}
+	return mc.FormatDSN()
+}
+
+// ConnMaxLifetimeDuration 返回连接最大存活时间
+func (d DatabaseConfig) ConnMaxLifetimeDuration() time.Duration {
+	return time.Duration(d.ConnMaxLifetime) * time.Second
+}
+
+// AttributeRefreshDuration 返回域名属性缓存的刷新间隔
+func (e EstimationConfig) AttributeRefreshDuration() time.Duration {
+	return time.Duration(e.AttributeRefresh) * time.Second
+}
+
+// CacheTTLDuration 返回动态属性缓存有效期
+func (d DynamicConfig) CacheTTLDuration() time.Duration {
+	return time.Duration(d.CacheTTL) * time.Second
+}
+
+// TimeoutDuration 返回单个数据源的获取超时
+func (d DynamicConfig) TimeoutDuration() time.Duration {
+	return time.Duration(d.Timeout) * time.Second
+}
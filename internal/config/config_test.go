@@ -0,0 +1,98 @@
+package config
+
+import "testing"
+
+func TestApplyEnv(t *testing.T) {
+	cfg := Default()
+	err := cfg.ApplyEnv([]string{
+		"DOMAINWEB_SERVER_PORT=9090",
+		"DOMAINWEB_DATABASE_MAX_OPEN_CONNS=20",
+		"DOMAINWEB_DATABASE_DB_NAME=test",
+		"DOMAINWEB_DYNAMIC_RDAP_TIMEOUT=3",
+		"DOMAINWEB_DYNAMIC_WHOIS_DISCOVERY=false",
+		"DOMAINWEB_DYNAMIC_PROVIDERS_MOCK_WHOIS=false",
+		"DOMAINWEB_DYNAMIC_WHOIS_SERVERS_COM=whois.example.com:43",
+		"DOMAINWEB_FOO_BAR=1",
+		"DOMAINWEB_DYNAMIC_RDAP=1",
+		"OTHER_SERVER_PORT=1",
+	})
+	if err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %d, want 9090", cfg.Server.Port)
+	}
+	if cfg.Database.MaxOpenConns != 20 {
+		t.Errorf("Database.MaxOpenConns = %d, want 20", cfg.Database.MaxOpenConns)
+	}
+	if cfg.Database.DBName != "test" {
+		t.Errorf("Database.DBName = %q, want test", cfg.Database.DBName)
+	}
+	if cfg.Dynamic.RDAP.Timeout != 3 {
+		t.Errorf("Dynamic.RDAP.Timeout = %d, want 3", cfg.Dynamic.RDAP.Timeout)
+	}
+	if cfg.Dynamic.Whois.Discovery {
+		t.Error("Dynamic.Whois.Discovery = true, want false")
+	}
+	if enabled, ok := cfg.Dynamic.Providers["mock_whois"]; !ok || enabled {
+		t.Errorf("Dynamic.Providers[mock_whois] = %v, %v, want false, true", enabled, ok)
+	}
+	if got := cfg.Dynamic.Whois.Servers["com"]; got != "whois.example.com:43" {
+		t.Errorf("Dynamic.Whois.Servers[com] = %q, want whois.example.com:43", got)
+	}
+}
+
+func TestApplyEnvInvalidValue(t *testing.T) {
+	cfg := Default()
+	if err := cfg.ApplyEnv([]string{"DOMAINWEB_DYNAMIC_RDAP_TIMEOUT=soon"}); err == nil {
+		t.Error("ApplyEnv() error = nil, want error for non-integer value")
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"server.port", "9090", false},
+		{"dynamic.rdap.timeout", "5", false},
+		{"dynamic.providers.rdap", "false", false},
+		{"dynamic.rdap.servers.co.uk", "https://rdap.example.com/", false},
+		{"server", "1", true},
+		{"foo.bar", "1", true},
+		{"server.foo", "1", true},
+		{"dynamic.rdap", "1", true},
+		{"dynamic.providers.rdap", "maybe", true},
+	}
+	for _, tt := range tests {
+		cfg := Default()
+		err := cfg.Set(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+
+	cfg := Default()
+	if err := cfg.Set("dynamic.rdap.servers.co.uk", "https://rdap.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Dynamic.RDAP.Servers["co.uk"]; got != "https://rdap.example.com/" {
+		t.Errorf("Dynamic.RDAP.Servers[co.uk] = %q", got)
+	}
+}
+
+func TestValidateDeterministicLiveProviders(t *testing.T) {
+	cfg := Default()
+	cfg.Dynamic.MockMode = MockModeDeterministic
+	cfg.Dynamic.Providers = map[string]bool{"rdap": false, "mock_whois": true}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cfg.Dynamic.Providers["rdap"] = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for rdap in deterministic mode")
+	}
+}
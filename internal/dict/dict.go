@@ -0,0 +1,170 @@
+// Package dict 提供内置的常用英文单词表，并将字母域名切分为单词，如 cloudshop -> cloud shop
+//
+// 单词表按词频从高到低排列，行号即词频排名；每个单词标注一个最常用的词性。
+package dict
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed words.txt
+var embeddedWords []byte
+
+// 词性
+const (
+	Noun        = "n"
+	Verb        = "v"
+	Adjective   = "adj"
+	Adverb      = "adv"
+	Preposition = "prep"
+	Pronoun     = "pron"
+	Conjunction = "conj"
+	Determiner  = "det"
+	Numeral     = "num"
+)
+
+// 单词表中单词的最大长度，超过的行视为格式错误
+const maxWordBytes = 32
+
+// Word 是单词表中的一个单词
+type Word struct {
+	Text string // 单词，小写
+	Rank int    // 词频排名，从1开始，数值越小越常用
+	POS  string // 词性，如 n、v、adj
+}
+
+// Dictionary 是英文单词表
+type Dictionary struct {
+	words   map[string]Word
+	longest int // 最长单词的长度，切分时限制查找范围
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDict *Dictionary
+	defaultErr  error
+)
+
+// Default 返回内置的单词表
+func Default() (*Dictionary, error) {
+	defaultOnce.Do(func() {
+		defaultDict, defaultErr = Parse(bytes.NewReader(embeddedWords))
+	})
+	return defaultDict, defaultErr
+}
+
+// Parse 读取单词表，每行为“单词 词性”，以 # 开头的行和空行被忽略
+// 重复的单词保留排名靠前的一个
+func Parse(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{words: make(map[string]Word)}
+
+	rank := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 || len(fields[0]) > maxWordBytes {
+			return nil, fmt.Errorf("单词表第 %d 行格式错误: %q", line, text)
+		}
+		word := strings.ToLower(fields[0])
+		if _, ok := d.words[word]; ok {
+			continue
+		}
+
+		rank++
+		d.words[word] = Word{Text: word, Rank: rank, POS: fields[1]}
+		if len(word) > d.longest {
+			d.longest = len(word)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取单词表失败: %w", err)
+	}
+	return d, nil
+}
+
+// Len 返回单词表中的单词数量
+func (d *Dictionary) Len() int {
+	return len(d.words)
+}
+
+// Words 返回单词表中的所有单词，按词频排名排列
+func (d *Dictionary) Words() []Word {
+	words := make([]Word, 0, len(d.words))
+	for _, w := range d.words {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool { return words[i].Rank < words[j].Rank })
+	return words
+}
+
+// Lookup 查找单词，不区分大小写
+func (d *Dictionary) Lookup(word string) (Word, bool) {
+	w, ok := d.words[strings.ToLower(word)]
+	return w, ok
+}
+
+// Segment 将字母串完整切分为单词表中的单词，无法完整切分时返回 nil
+// 连字符视为单词之间的分隔；有多种切分方式时取单词最少的一种，单词数相同时取最常用单词排名之和最小的一种
+func (d *Dictionary) Segment(s string) []Word {
+	s = strings.ToLower(s)
+	var words []Word
+	for _, part := range strings.Split(s, "-") {
+		segment := d.segment(part)
+		if segment == nil {
+			return nil
+		}
+		words = append(words, segment...)
+	}
+	return words
+}
+
+// segment 切分不含连字符的字母串
+func (d *Dictionary) segment(s string) []Word {
+	n := len(s)
+	if n == 0 {
+		return nil
+	}
+
+	// count[i] 和 cost[i] 为 s[i:] 切分所需的最少单词数及对应的排名之和，count 为-1表示无法切分；
+	// next[i] 为第一个单词的结束位置
+	count := make([]int, n+1)
+	cost := make([]int, n+1)
+	next := make([]int, n+1)
+	for i := 0; i < n; i++ {
+		count[i] = -1
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j <= n && j-i <= d.longest; j++ {
+			w, ok := d.words[s[i:j]]
+			if !ok || count[j] < 0 {
+				continue
+			}
+			c, r := count[j]+1, cost[j]+w.Rank
+			if count[i] < 0 || c < count[i] || c == count[i] && r < cost[i] {
+				count[i], cost[i], next[i] = c, r, j
+			}
+		}
+	}
+	if count[0] < 0 {
+		return nil
+	}
+
+	words := make([]Word, 0, count[0])
+	for i := 0; i < n; i = next[i] {
+		words = append(words, d.words[s[i:next[i]]])
+	}
+	return words
+}
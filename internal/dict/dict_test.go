@@ -0,0 +1,100 @@
+package dict
+
+import (
+	"strings"
+	"testing"
+)
+
+// texts 返回切分结果中的单词，以空格分隔
+func texts(words []Word) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestSegment(t *testing.T) {
+	d, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"cloudshop", "cloud shop"},
+		{"CloudShop", "cloud shop"},
+		{"cloud-shop", "cloud shop"},
+		{"shop", "shop"},
+		// 单词最少的切分优先，island 不切分为 is land
+		{"island", "island"},
+		// 无法完整切分
+		{"cloudxq", ""},
+		{"zzqx", ""},
+		{"cloud-", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := texts(d.Segment(tt.input)); got != tt.want {
+			t.Errorf("Segment(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	words := d.Segment("cloudshop")
+	cloud, _ := d.Lookup("cloud")
+	shop, _ := d.Lookup("Shop")
+	if len(words) != 2 || words[0] != cloud || words[1] != shop {
+		t.Errorf("Segment(cloudshop) = %+v", words)
+	}
+	if cloud.POS != Noun || cloud.Rank == 0 || shop.Rank >= cloud.Rank {
+		t.Errorf("Lookup: cloud = %+v, shop = %+v", cloud, shop)
+	}
+}
+
+func TestSegmentPreference(t *testing.T) {
+	d, err := Parse(strings.NewReader("# 测试\nnow adv\nno det\nhere adv\nwhere adv\nnowhere adv\nno det\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if d.Len() != 5 {
+		t.Errorf("Len() = %d, want 5 (重复的单词只保留一个)", d.Len())
+	}
+	if w, _ := d.Lookup("no"); w.Rank != 2 {
+		t.Errorf("Lookup(no).Rank = %d, want 2", w.Rank)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// 最长匹配：一个单词优于两个单词
+		{"nowhere", "nowhere"},
+		// 单词数相同时取排名之和最小的切分：now here (1+3) 优于 no where (2+4)
+		{"nowherenowhere", "nowhere nowhere"},
+		{"nowhere-now", "nowhere now"},
+		{"herenow", "here now"},
+	}
+	for _, tt := range tests {
+		if got := texts(d.Segment(tt.input)); got != tt.want {
+			t.Errorf("Segment(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	d, err = Parse(strings.NewReader("now adv\nno det\nhere adv\nwhere adv\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(d.Segment("nowhere")); got != "now here" {
+		t.Errorf("Segment(nowhere) = %q, want now here", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{"cloud\n", "cloud n extra\n", strings.Repeat("a", maxWordBytes+1) + " n\n"} {
+		if _, err := Parse(strings.NewReader(data)); err == nil {
+			t.Errorf("Parse(%q) error = nil, want error", data)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package export 提供估价结果和查询历史的导出格式
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"domainweb/internal/model"
+)
+
+// WriteEstimationsCSV 以CSV格式输出批量估价结果，属性列为以 | 分隔的属性名称
+func WriteEstimationsCSV(w io.Writer, items []model.BatchEstimationItem) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"domain", "grade", "price", "base_attributes", "other_attributes", "estimation_date", "error"}); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		record := []string{item.Domain, "", "", "", "", "", item.Error}
+		if r := item.Result; r != nil {
+			record[1] = strconv.FormatFloat(r.Grade, 'f', 2, 64)
+			record[2] = strconv.FormatFloat(r.Price, 'f', 2, 64)
+			record[3] = attributeNames(r.BaseAttributes)
+			record[4] = attributeNames(r.OtherAttributes)
+			record[5] = r.EstimationDate.Format("2006-01-02 15:04:05")
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteHistoryCSV 以CSV格式输出查询历史
+func WriteHistoryCSV(w io.Writer, records []model.HistoryRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "domain", "grade", "price", "rule_set_version", "estimation_date"}); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if err := cw.Write([]string{
+			strconv.FormatInt(r.ID, 10),
+			r.Domain,
+			strconv.FormatFloat(r.Grade, 'f', 2, 64),
+			strconv.FormatFloat(r.Price, 'f', 2, 64),
+			strconv.FormatInt(r.RuleSetVersion, 10),
+			r.EstimationDate.Format("2006-01-02 15:04:05"),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// attributeNames 将属性名称用 | 连接
+func attributeNames(attrs []model.AttributeDetail) string {
+	names := make([]string, len(attrs))
+	for i, attr := range attrs {
+		names[i] = attr.Name
+	}
+	return strings.Join(names, "|")
+}
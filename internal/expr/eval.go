@@ -0,0 +1,315 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// node 是语法树节点，typ 返回编译时推断的类型
+type node interface {
+	typ() Type
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+// 运行时的值只有 float64、string、bool 和 []interface{} 四种
+type literal struct {
+	value interface{}
+	t     Type
+}
+
+func (n *literal) typ() Type { return n.t }
+
+func (n *literal) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type variable struct {
+	name string
+	t    Type
+}
+
+func (n *variable) typ() Type { return n.t }
+
+func (n *variable) eval(env map[string]interface{}) (interface{}, error) {
+	raw, ok := env[n.name]
+	if !ok || raw == nil {
+		return nil, fmt.Errorf("变量 %s 没有值", n.name)
+	}
+	v, err := normalize(raw)
+	if err != nil {
+		return nil, fmt.Errorf("变量 %s: %w", n.name, err)
+	}
+	if n.t != Any && valueType(v) != n.t {
+		return nil, fmt.Errorf("变量 %s 应为%s，实际为%s", n.name, n.t, valueType(v))
+	}
+	return v, nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) typ() Type { return List }
+
+func (n *listNode) eval(env map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) typ() Type {
+	if n.op == "!" {
+		return Bool
+	}
+	return Number
+}
+
+func (n *unaryNode) eval(env map[string]interface{}) (interface{}, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("! 的操作数必须是布尔值，实际为%s", valueType(v))
+		}
+		return !b, nil
+	}
+	f, ok := asNumber(v)
+	if !ok {
+		return nil, fmt.Errorf("- 的操作数必须是数字，实际为%s", valueType(v))
+	}
+	return -f, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+	t           Type
+}
+
+func (n *binaryNode) typ() Type { return n.t }
+
+func (n *binaryNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算短路求值
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s 两侧必须是布尔值，实际为%s", n.op, valueType(l))
+		}
+		if lb == (n.op == "||") {
+			return lb, nil
+		}
+		r, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s 两侧必须是布尔值，实际为%s", n.op, valueType(r))
+		}
+		return rb, nil
+	}
+
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		eq, err := equal(l, r)
+		if err != nil {
+			return nil, err
+		}
+		return eq == (n.op == "=="), nil
+
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		}
+		return c >= 0, nil
+
+	case "in":
+		list, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("in 的右侧必须是列表，实际为%s", valueType(r))
+		}
+		for _, item := range list {
+			// 类型不同的元素视为不相等
+			if eq, err := equal(l, item); err == nil && eq {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "+":
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+
+	a, aok := asNumber(l)
+	b, bok := asNumber(r)
+	if !aok || !bok {
+		return nil, fmt.Errorf("%s 两侧必须是数字，实际为%s和%s", n.op, valueType(l), valueType(r))
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("除数为0")
+		}
+		return a / b, nil
+	case "%":
+		if b == 0 {
+			return nil, fmt.Errorf("除数为0")
+		}
+		return math.Mod(a, b), nil
+	}
+	return nil, fmt.Errorf("不支持的运算符: %s", n.op)
+}
+
+// equal 判断两个值是否相等，数字和数字字符串按数值比较
+func equal(l, r interface{}) (bool, error) {
+	switch lv := l.(type) {
+	case bool:
+		if rv, ok := r.(bool); ok {
+			return lv == rv, nil
+		}
+	case string:
+		if rv, ok := r.(string); ok {
+			return lv == rv, nil
+		}
+	}
+
+	if a, ok := asNumber(l); ok {
+		if b, ok := asNumber(r); ok {
+			return a == b, nil
+		}
+	}
+	return false, fmt.Errorf("无法比较%s和%s", valueType(l), valueType(r))
+}
+
+// compare 比较两个数字或两个字符串的大小
+func compare(l, r interface{}) (int, error) {
+	if ls, ok := l.(string); ok {
+		if rs, ok := r.(string); ok {
+			return strings.Compare(ls, rs), nil
+		}
+	}
+
+	a, aok := asNumber(l)
+	b, bok := asNumber(r)
+	if !aok || !bok {
+		return 0, fmt.Errorf("无法比较%s和%s的大小", valueType(l), valueType(r))
+	}
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// asNumber 将数字或数字字符串转换为float64，动态属性中的数字可能以字符串形式提供
+func asNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// normalize 将变量值转换为运行时使用的类型
+func normalize(v interface{}) (interface{}, error) {
+	switch n := v.(type) {
+	case float64, string, bool:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case []string:
+		list := make([]interface{}, len(n))
+		for i, s := range n {
+			list[i] = s
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]interface{}, len(n))
+		for i, item := range n {
+			value, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("不支持的值类型 %T", v)
+}
+
+// valueType 返回运行时值的类型
+func valueType(v interface{}) Type {
+	switch v.(type) {
+	case float64:
+		return Number
+	case string:
+		return String
+	case bool:
+		return Bool
+	case []interface{}:
+		return List
+	}
+	return Any
+}
@@ -0,0 +1,143 @@
+// Package expr 实现估价规则使用的条件表达式语言
+//
+// 表达式支持数字、字符串、布尔值和列表字面量，以及以下运算（优先级从低到高）：
+//
+//	||
+//	&&
+//	!
+//	== != < <= > >= in
+//	+ -
+//	* / %
+//	-（取负）
+//
+// 例如 length <= 4 && structure == "纯字母" && tld in ["com", "net"]。
+// 表达式只能读取编译时声明的变量，不能调用函数或修改任何数据，
+// 编译阶段会检查未知变量和可以确定的类型错误。
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 表达式的长度和嵌套深度限制，防止构造过大的表达式
+const (
+	MaxLength = 2000
+	MaxDepth  = 64
+)
+
+// Type 表示表达式的静态类型
+type Type int
+
+// 支持的类型，Any 表示运行时才能确定的类型（如动态属性）
+const (
+	Any Type = iota
+	Number
+	String
+	Bool
+	List
+)
+
+// String 返回类型的中文名称
+func (t Type) String() string {
+	switch t {
+	case Number:
+		return "数字"
+	case String:
+		return "字符串"
+	case Bool:
+		return "布尔"
+	case List:
+		return "列表"
+	}
+	return "任意"
+}
+
+// Scope 提供编译时可用的变量及其类型
+type Scope interface {
+	Lookup(name string) (Type, bool)
+}
+
+// Vars 是以map实现的Scope
+type Vars map[string]Type
+
+// Lookup 查找变量的类型
+func (v Vars) Lookup(name string) (Type, bool) {
+	t, ok := v[name]
+	return t, ok
+}
+
+// Error 表示表达式的编译错误，Pos 为出错的字符位置（从1开始）
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("第 %d 个字符: %s", e.Pos, e.Msg)
+}
+
+// Program 是编译后的表达式，可以并发执行
+type Program struct {
+	source    string
+	root      node
+	variables []string
+}
+
+// Compile 编译表达式，表达式的结果必须是布尔值
+func Compile(source string, scope Scope) (*Program, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("表达式不能为空")
+	}
+	if utf8.RuneCountInString(source) > MaxLength {
+		return nil, fmt.Errorf("表达式长度不能超过 %d 个字符", MaxLength)
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, scope: scope, variables: make(map[string]bool)}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if t := root.typ(); t != Bool && t != Any {
+		return nil, &Error{Pos: 1, Msg: fmt.Sprintf("表达式的结果必须是布尔值，实际为%s", t)}
+	}
+
+	variables := make([]string, 0, len(p.variables))
+	for name := range p.variables {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+
+	return &Program{source: source, root: root, variables: variables}, nil
+}
+
+// String 返回表达式的源文本
+func (p *Program) String() string {
+	return p.source
+}
+
+// Variables 返回表达式引用的变量，按名称排序
+func (p *Program) Variables() []string {
+	return p.variables
+}
+
+// Eval 使用给定的变量值执行表达式
+// 变量缺失、类型不匹配或除数为0时返回错误
+func (p *Program) Eval(env map[string]interface{}) (bool, error) {
+	v, err := p.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("表达式的结果不是布尔值: %v", v)
+	}
+	return b, nil
+}
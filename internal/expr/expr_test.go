@@ -0,0 +1,212 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testVars = Vars{
+	"length":    Number,
+	"tld":       String,
+	"structure": String,
+	"patterns":  List,
+	"hit":       Bool,
+	"rank":      Any,
+	"volume":    Any,
+}
+
+var testEnv = map[string]interface{}{
+	"length":    4,
+	"tld":       "com",
+	"structure": "纯字母",
+	"patterns":  []string{"AABB", "含8"},
+	"hit":       true,
+	"rank":      200,
+	"volume":    "5000",
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		// 优先级
+		{"1 + 2 * 3 == 7", true},
+		{"(1 + 2) * 3 == 9", true},
+		{"10 - 4 - 3 == 3", true},
+		{"12 / 3 / 2 == 2", true},
+		{"7 % 4 == 3", true},
+		{"-2 * 3 == -6", true},
+		{"--2 == 2", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!false && false", false},
+		{"!(false && false)", true},
+		{"length + 1 > 4 && tld == \"com\"", true},
+		{"1 < 2 && 2 < 3 || false", true},
+
+		// in 列表
+		{`tld in ["com", "net"]`, true},
+		{`tld in ["org", "net"]`, false},
+		{`"AABB" in patterns`, true},
+		{`"ABAB" in patterns`, false},
+		{"length in [3, 4, 5]", true},
+		{"length in []", false},
+		{`rank in [100, "200"]`, true},
+		{`!(tld in ["org"])`, true},
+
+		// 字符串与比较
+		{`tld + ".cn" == "com.cn"`, true},
+		{`structure != "纯数字"`, true},
+		{`"abc" < "abd"`, true},
+		{`structure == "纯字母" && length <= 4`, true},
+
+		// 动态属性按运行时的值比较，数字字符串按数值比较
+		{"rank <= 300", true},
+		{"volume / rank > 20", true},
+		{"volume == 5000", true},
+		{"hit", true},
+		{"!hit", false},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.source, testVars)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.source, err)
+			continue
+		}
+		got, err := p.Eval(testEnv)
+		if err != nil {
+			t.Errorf("Eval(%q) error = %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		env    map[string]interface{}
+		want   string
+	}{
+		{"length / 0 > 1", testEnv, "除数为0"},
+		{"length % (length - 4) > 1", testEnv, "除数为0"},
+		{"rank / (volume - 5000) > 1", testEnv, "除数为0"},
+		{"rank > 100", map[string]interface{}{}, "变量 rank 没有值"},
+		{"rank > 100", map[string]interface{}{"rank": "高"}, "无法比较"},
+		{"rank * 2 > 100", map[string]interface{}{"rank": "高"}, "两侧必须是数字"},
+		{"rank", map[string]interface{}{"rank": 1}, "布尔"},
+		{"length > 1", map[string]interface{}{"length": "长"}, "应为数字"},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.source, testVars)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.source, err)
+			continue
+		}
+		_, err = p.Eval(tt.env)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Eval(%q) error = %v, want error containing %q", tt.source, err, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		// 未知变量
+		{"unknown > 1", "未知变量: unknown"},
+		{"length > 1 && Length < 5", "未知变量: Length"},
+
+		// 编译时可以确定的类型错误
+		{"length", "结果必须是布尔值"},
+		{"tld + 1 == 2", "+ 两侧的类型不匹配"},
+		{"hit + hit", "+ 只能用于数字相加或字符串连接"},
+		{"1 < 2 == true", "比较运算不能连续使用"},
+		{`length == "4"`, "类型不匹配"},
+		{"tld > 1", "类型不匹配"},
+		{"hit < true", "只能比较数字或字符串"},
+		{"length && hit", "两侧必须是布尔值"},
+		{"!length", "的操作数必须是"},
+		{`-tld == "com"`, "的操作数必须是"},
+		{"tld in tld", "in 的右侧必须是列表"},
+		{`patterns in ["AABB"]`, "in 的左侧不能是列表"},
+		{`patterns == ["AABB"]`, "请使用 in"},
+		{"length * tld > 1", "两侧必须是数字"},
+		{"[[1]] == 1", "列表不能嵌套"},
+
+		// 语法错误
+		{"", "不能为空"},
+		{"   ", "不能为空"},
+		{"length >", "需要一个值"},
+		{"(length > 1", "缺少 )"},
+		{"length > 1)", "多余的内容"},
+		{"1 < length < 5", "比较运算不能连续使用"},
+		{`tld == "com`, "缺少结束引号"},
+		{`tld == "\q"`, "无效的转义字符"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.source, testVars)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) error = %v, want error containing %q", tt.source, err, tt.want)
+		}
+	}
+}
+
+func TestCompileErrorPosition(t *testing.T) {
+	_, err := Compile("length > 1 && foo", testVars)
+	var exprErr *Error
+	if !errors.As(err, &exprErr) {
+		t.Fatalf("Compile() error = %v, want *Error", err)
+	}
+	if exprErr.Pos != 15 {
+		t.Errorf("Pos = %d, want 15", exprErr.Pos)
+	}
+}
+
+func TestCompileLimits(t *testing.T) {
+	nested := func(n int) string {
+		return strings.Repeat("(", n) + "hit" + strings.Repeat(")", n)
+	}
+
+	// 最外层的表达式计为第一层，因此最多可以嵌套 MaxDepth-1 层括号
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"嵌套层数未超限", nested(MaxDepth - 1), ""},
+		{"嵌套层数超限", nested(MaxDepth), "嵌套层数不能超过 64"},
+		{"取反层数超限", strings.Repeat("!", MaxDepth) + "hit", "嵌套层数不能超过 64"},
+		{"长度未超限", "hit" + strings.Repeat(" ", MaxLength-3), ""},
+		{"长度超限", "hit" + strings.Repeat(" ", MaxLength-2), "长度不能超过 2000"},
+		{"长度按字符计算", `tld == "` + strings.Repeat("中", MaxLength-9) + `"`, ""},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.source, testVars)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: Compile() error = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Compile() error = %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVariables(t *testing.T) {
+	p, err := Compile(`tld == "com" && length < 5 && (rank < 100 || length > 2)`, testVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(p.Variables(), ","); got != "length,rank,tld" {
+		t.Errorf("Variables() = %s, want length,rank,tld", got)
+	}
+}
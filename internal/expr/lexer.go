@@ -0,0 +1,127 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind 表示词法单元的类型
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOperator // 运算符和括号、逗号等标点
+)
+
+// token 表示一个词法单元
+type token struct {
+	kind tokenKind
+	text string  // 原始文本，字符串为解码后的内容
+	num  float64 // 数字的值
+	pos  int     // 在表达式中的字符位置，从1开始
+}
+
+// 按长度从长到短排列，保证优先匹配较长的运算符
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",",
+}
+
+// lex 将表达式拆分为词法单元
+func lex(src string) ([]token, error) {
+	var tokens []token
+	offset := 0
+	for offset < len(src) {
+		r, size := utf8.DecodeRuneInString(src[offset:])
+		pos := utf8.RuneCountInString(src[:offset]) + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			offset += size
+
+		case r >= '0' && r <= '9' || r == '.' && offset+1 < len(src) && src[offset+1] >= '0' && src[offset+1] <= '9':
+			end := offset
+			for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.' || src[end] == '_') {
+				end++
+			}
+			text := src[offset:end]
+			num, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if err != nil {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("无效的数字: %s", text)}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: num, pos: pos})
+			offset = end
+
+		case r == '"' || r == '\'':
+			text, end, err := lexString(src, offset)
+			if err != nil {
+				return nil, &Error{Pos: pos, Msg: err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: pos})
+			offset = end
+
+		case r == '_' || unicode.IsLetter(r):
+			end := offset
+			for end < len(src) {
+				c, n := utf8.DecodeRuneInString(src[end:])
+				if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					break
+				}
+				end += n
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[offset:end], pos: pos})
+			offset = end
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[offset:], op) {
+					tokens = append(tokens, token{kind: tokOperator, text: op, pos: pos})
+					offset += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("无法识别的字符: %q", r)}
+			}
+		}
+	}
+
+	pos := utf8.RuneCountInString(src) + 1
+	return append(tokens, token{kind: tokEOF, pos: pos}), nil
+}
+
+// lexString 读取以单引号或双引号包围的字符串，支持 \" \' \\ \n \t 转义
+func lexString(src string, offset int) (string, int, error) {
+	quote := src[offset]
+	var b strings.Builder
+	for i := offset + 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '\'':
+				b.WriteByte(src[i])
+			default:
+				return "", 0, fmt.Errorf("无效的转义字符: \\%c", src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("字符串缺少结束引号")
+}
@@ -0,0 +1,388 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// 比较运算符，in 以标识符的形式出现
+var comparisonOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+// parser 以递归下降的方式解析表达式，同时完成类型检查
+type parser struct {
+	tokens    []token
+	pos       int
+	depth     int
+	scope     Scope
+	variables map[string]bool // 表达式引用的变量
+}
+
+// parse 解析整个表达式
+func (p *parser) parse() (node, error) {
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "多余的内容: %s", tok.text)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptOperator 在下一个词法单元是指定运算符时消费它
+func (p *parser) acceptOperator(ops ...string) (token, bool) {
+	tok := p.peek()
+	if tok.kind != tokOperator {
+		return tok, false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return p.next(), true
+		}
+	}
+	return tok, false
+}
+
+func (p *parser) expectOperator(op string) error {
+	if _, ok := p.acceptOperator(op); !ok {
+		tok := p.peek()
+		return p.errorf(tok, "缺少 %s，实际为 %s", op, describe(tok))
+	}
+	return nil
+}
+
+// enter 记录嵌套深度，超过 MaxDepth 时返回错误
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return p.errorf(p.peek(), "表达式嵌套层数不能超过 %d", MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return &Error{Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.acceptOperator("||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.binary(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.acceptOperator("&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.binary(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	tok, ok := p.acceptOperator("!")
+	if !ok {
+		return p.parseComparison()
+	}
+
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return p.unary(tok, x)
+}
+
+// parseComparison 解析比较运算，比较运算不能连续使用，如 a < b < c
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	isComparison := tok.kind == tokOperator && comparisonOperators[tok.text]
+	if !isComparison && !(tok.kind == tokIdent && tok.text == "in") {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind == tokOperator && comparisonOperators[next.text] || next.kind == tokIdent && next.text == "in" {
+		return nil, p.errorf(next, "比较运算不能连续使用，请使用 && 连接")
+	}
+	return p.binary(tok, left, right)
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.binary(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.acceptOperator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.binary(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok, ok := p.acceptOperator("-")
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return p.unary(tok, x)
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return &literal{value: tok.num, t: Number}, nil
+
+	case tokString:
+		return &literal{value: tok.text, t: String}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return &literal{value: tok.text == "true", t: Bool}, nil
+		case "in":
+			return nil, p.errorf(tok, "in 前缺少要查找的值")
+		}
+		t, ok := p.scope.Lookup(tok.text)
+		if !ok {
+			return nil, p.errorf(tok, "未知变量: %s", tok.text)
+		}
+		p.variables[tok.text] = true
+		return &variable{name: tok.text, t: t}, nil
+
+	case tokOperator:
+		switch tok.text {
+		case "(":
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case "[":
+			return p.parseList(tok)
+		}
+	}
+	return nil, p.errorf(tok, "此处需要一个值，实际为 %s", describe(tok))
+}
+
+// parseList 解析列表字面量，如 ["com", "net"]
+func (p *parser) parseList(open token) (node, error) {
+	list := &listNode{}
+	if _, ok := p.acceptOperator("]"); ok {
+		return list, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if item.typ() == List {
+			return nil, p.errorf(open, "列表不能嵌套")
+		}
+		list.items = append(list.items, item)
+
+		if _, ok := p.acceptOperator("]"); ok {
+			return list, nil
+		}
+		if err := p.expectOperator(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// unary 创建一元运算节点并检查操作数类型
+func (p *parser) unary(tok token, x node) (node, error) {
+	want := Number
+	if tok.text == "!" {
+		want = Bool
+	}
+	if !compatible(x.typ(), want) {
+		return nil, p.errorf(tok, "%s 的操作数必须是%s，实际为%s", tok.text, want, x.typ())
+	}
+	return &unaryNode{op: tok.text, x: x}, nil
+}
+
+// binary 创建二元运算节点并检查两侧的类型
+func (p *parser) binary(tok token, left, right node) (node, error) {
+	op := tok.text
+	lt, rt := left.typ(), right.typ()
+
+	mismatch := func() error {
+		return p.errorf(tok, "%s 两侧的类型不匹配: %s 和 %s", op, lt, rt)
+	}
+
+	result := Bool
+	switch op {
+	case "&&", "||":
+		if !compatible(lt, Bool) || !compatible(rt, Bool) {
+			return nil, p.errorf(tok, "%s 两侧必须是布尔值，实际为%s和%s", op, lt, rt)
+		}
+
+	case "==", "!=":
+		if lt == List || rt == List {
+			return nil, p.errorf(tok, "列表不能使用 %s 比较，请使用 in", op)
+		}
+		if !comparable(lt, rt) {
+			return nil, mismatch()
+		}
+
+	case "<", "<=", ">", ">=":
+		if lt == Bool || lt == List || rt == Bool || rt == List {
+			return nil, p.errorf(tok, "%s 只能比较数字或字符串，实际为%s和%s", op, lt, rt)
+		}
+		if !comparable(lt, rt) {
+			return nil, mismatch()
+		}
+
+	case "in":
+		if rt != List && rt != Any {
+			return nil, p.errorf(tok, "in 的右侧必须是列表，实际为%s", rt)
+		}
+		if lt == List {
+			return nil, p.errorf(tok, "in 的左侧不能是列表")
+		}
+
+	case "+":
+		if lt == Bool || lt == List || rt == Bool || rt == List {
+			return nil, p.errorf(tok, "+ 只能用于数字相加或字符串连接，实际为%s和%s", lt, rt)
+		}
+		if !comparable(lt, rt) {
+			return nil, mismatch()
+		}
+		result = lt
+		if result == Any {
+			result = rt
+		}
+
+	default: // - * / %
+		if !compatible(lt, Number) || !compatible(rt, Number) {
+			return nil, p.errorf(tok, "%s 两侧必须是数字，实际为%s和%s", op, lt, rt)
+		}
+		result = Number
+	}
+
+	return &binaryNode{op: op, left: left, right: right, t: result}, nil
+}
+
+// compatible 判断类型是否可以用作期望的类型
+func compatible(t, want Type) bool {
+	return t == Any || t == want
+}
+
+// comparable 判断两个类型能否互相比较，任一侧类型未知时在运行时检查
+func comparable(a, b Type) bool {
+	return a == Any || b == Any || a == b
+}
+
+// describe 返回词法单元在错误信息中的描述
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return "表达式结尾"
+	case tokString:
+		return fmt.Sprintf("%q", tok.text)
+	}
+	return tok.text
+}
@@ -0,0 +1,368 @@
+// Package migrate 实现带版本号的数据库结构迁移
+//
+// 迁移文件内嵌在 migrations/<方言>/ 目录中，命名为 NNNN_名称.up.sql 和
+// NNNN_名称.down.sql，已应用的版本记录在 schema_migrations 表中。
+package migrate
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"domainweb/internal/repository"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// 迁移文件名格式，如 0001_init.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 表示一个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string // 升级SQL
+	Down    string // 回滚SQL
+}
+
+// Status 表示一个迁移的应用状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator 负责在数据库上应用和回滚迁移
+type Migrator struct {
+	db         *sql.DB
+	dialect    repository.Dialect
+	migrations []Migration
+}
+
+// New 创建一个新的Migrator实例，加载该方言的内嵌迁移文件
+func New(db *sql.DB, dialect repository.Dialect) (*Migrator, error) {
+	migrations, err := load(migrationFiles, path.Join("migrations", string(dialect)))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Migrations 返回所有迁移，按版本号升序排列
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up 应用所有未执行的迁移，返回本次应用的迁移
+func (m *Migrator) Up() ([]Migration, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if err := m.run(mig, mig.Up, true); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down 按版本号从高到低回滚最近的 steps 个已应用迁移，返回本次回滚的迁移
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("回滚步数必须大于0")
+	}
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if err := m.run(mig, mig.Down, false); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Status 返回所有迁移的应用状态
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		appliedAt, ok := applied[mig.Version]
+		statuses = append(statuses, Status{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// Pending 返回未应用的迁移数量
+func (m *Migrator) Pending() (int, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// prepare 创建版本记录表，并接管迁移机制引入前已初始化的数据库
+func (m *Migrator) prepare() error {
+	exists, err := m.tableExists("schema_migrations")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := m.db.Exec(m.createTableSQL()); err != nil {
+		return fmt.Errorf("创建迁移版本表失败: %w", err)
+	}
+
+	// 通过 scripts/init_db.sql 或旧版本程序创建的数据库已具备初始表结构，
+	// 直接将初始迁移标记为已应用，避免重复建表和重复写入初始数据
+	hasSchema, err := m.tableExists("domain_attributes")
+	if err != nil {
+		return err
+	}
+	if hasSchema && len(m.migrations) > 0 {
+		baseline := m.migrations[0]
+		if _, err := m.db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			baseline.Version, baseline.Name, time.Now()); err != nil {
+			return fmt.Errorf("记录初始迁移失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// createTableSQL 返回创建版本记录表的SQL
+func (m *Migrator) createTableSQL() string {
+	if m.dialect == repository.SQLite {
+		return `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`
+	}
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at DATETIME NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`
+}
+
+// tableExists 判断数据表是否存在
+func (m *Migrator) tableExists(name string) (bool, error) {
+	query := `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`
+	if m.dialect == repository.SQLite {
+		query = `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`
+	}
+
+	var count int
+	if err := m.db.QueryRow(query, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("查询数据表 %s 失败: %w", name, err)
+	}
+	return count > 0, nil
+}
+
+// applied 返回已应用的迁移版本及应用时间
+func (m *Migrator) applied() (map[int64]time.Time, error) {
+	rows, err := m.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("查询迁移版本失败: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("读取迁移版本失败: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// run 在事务中执行迁移SQL并更新版本记录
+// MySQL的DDL语句会隐式提交事务，失败时需要根据错误信息手动处理
+func (m *Migrator) run(mig Migration, script string, up bool) error {
+	direction := "升级"
+	if !up {
+		direction = "回滚"
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("%s迁移 %04d_%s 失败: %w", direction, mig.Version, mig.Name, err)
+		}
+	}
+
+	if up {
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			mig.Version, mig.Name, time.Now())
+	} else {
+		_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, mig.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("更新迁移版本 %04d 失败: %w", mig.Version, err)
+	}
+
+	return tx.Commit()
+}
+
+// load 从目录中加载迁移文件，每个版本必须同时提供up和down文件
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录 %s 失败: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("迁移文件名格式无效: %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件 %s 失败: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		} else if mig.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本 %04d 存在不同的名称: %s 和 %s", version, mig.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if strings.TrimSpace(mig.Up) == "" || strings.TrimSpace(mig.Down) == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少up或down文件", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements 按分号拆分SQL脚本，忽略字符串和注释中的分号
+// MySQL驱动默认不支持一次执行多条语句，因此逐条执行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+	lineComment := false
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+				current.WriteRune(r)
+			}
+			continue
+		case quote != 0:
+			current.WriteRune(r)
+			if r == '\\' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			lineComment = true
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ';':
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
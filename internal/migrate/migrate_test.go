@@ -0,0 +1,109 @@
+package migrate_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"domainweb/internal/migrate"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+func countRows(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestUpDown(t *testing.T) {
+	db := testutil.OpenSQLite(t)
+	m, err := migrate.New(db, repository.SQLite)
+	if err != nil {
+		t.Fatalf("migrate.New() error = %v", err)
+	}
+	total := len(m.Migrations())
+
+	done, err := m.Up()
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(done) != total {
+		t.Fatalf("Up() applied %d migrations, want %d", len(done), total)
+	}
+	if pending, err := m.Pending(); err != nil || pending != 0 {
+		t.Fatalf("Pending() = %d, %v, want 0", pending, err)
+	}
+	if n := countRows(t, db, "SELECT COUNT(*) FROM domain_attributes"); n == 0 {
+		t.Error("初始数据中没有域名属性")
+	}
+
+	// 再次执行不会重复应用
+	if done, err := m.Up(); err != nil || len(done) != 0 {
+		t.Fatalf("second Up() = %d migrations, %v, want 0", len(done), err)
+	}
+
+	// 逐个回滚最近的迁移
+	done, err = m.Down(1)
+	if err != nil || len(done) != 1 || done[0].Version != m.Migrations()[total-1].Version {
+		t.Fatalf("Down(1) = %v, %v", done, err)
+	}
+	if pending, _ := m.Pending(); pending != 1 {
+		t.Errorf("Pending() = %d, want 1", pending)
+	}
+
+	// 回滚全部迁移后只保留版本记录表
+	if done, err = m.Down(total); err != nil || len(done) != total-1 {
+		t.Fatalf("Down(%d) = %d migrations, %v, want %d", total, len(done), err, total-1)
+	}
+	tables := countRows(t, db,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')")
+	if tables != 0 {
+		t.Errorf("回滚全部迁移后仍有 %d 个表", tables)
+	}
+
+	// 回滚后可以重新应用
+	if done, err := m.Up(); err != nil || len(done) != total {
+		t.Fatalf("Up() after Down() = %d migrations, %v, want %d", len(done), err, total)
+	}
+}
+
+func TestDownInvalidSteps(t *testing.T) {
+	m, err := migrate.New(testutil.OpenSQLite(t), repository.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Down(0); err == nil {
+		t.Error("Down(0) error = nil, want error")
+	}
+}
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	sqlite := migrations(t, repository.SQLite)
+	mysql := migrations(t, repository.MySQL)
+	if len(sqlite) != len(mysql) {
+		t.Fatalf("sqlite has %d migrations, mysql has %d", len(sqlite), len(mysql))
+	}
+	for i := range sqlite {
+		if sqlite[i].Version != mysql[i].Version || sqlite[i].Name != mysql[i].Name {
+			t.Errorf("migration %d: sqlite %04d_%s, mysql %04d_%s",
+				i, sqlite[i].Version, sqlite[i].Name, mysql[i].Version, mysql[i].Name)
+		}
+		if strings.TrimSpace(sqlite[i].Down) == "" || strings.TrimSpace(mysql[i].Down) == "" {
+			t.Errorf("migration %04d_%s 缺少回滚脚本", sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+}
+
+// migrations 返回该方言的内嵌迁移，加载迁移文件不需要数据库连接
+func migrations(t *testing.T, dialect repository.Dialect) []migrate.Migration {
+	t.Helper()
+	m, err := migrate.New(nil, dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.Migrations()
+}
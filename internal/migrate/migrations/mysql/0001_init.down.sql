@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS history_records;
+DROP TABLE IF EXISTS domain_attributes;
+DROP TABLE IF EXISTS domains;
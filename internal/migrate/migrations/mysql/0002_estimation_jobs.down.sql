@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS estimation_job_items;
+DROP TABLE IF EXISTS estimation_jobs;
@@ -0,0 +1,28 @@
+-- 异步批量估价任务
+CREATE TABLE IF NOT EXISTS estimation_jobs (
+    id VARCHAR(32) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL COMMENT '任务状态',
+    total INT NOT NULL COMMENT '域名总数',
+    done INT NOT NULL DEFAULT 0 COMMENT '估价成功的数量',
+    failed INT NOT NULL DEFAULT 0 COMMENT '估价失败的数量',
+    error TEXT COMMENT '任务失败的原因',
+    created_at DATETIME NOT NULL COMMENT '创建时间',
+    updated_at DATETIME NOT NULL COMMENT '最后更新时间',
+    finished_at DATETIME NULL COMMENT '结束时间',
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 估价任务中的域名及结果
+CREATE TABLE IF NOT EXISTS estimation_job_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    job_id VARCHAR(32) NOT NULL COMMENT '任务ID',
+    position INT NOT NULL COMMENT '在提交列表中的位置',
+    domain VARCHAR(255) NOT NULL COMMENT '提交的域名',
+    status VARCHAR(20) NOT NULL COMMENT '估价状态',
+    result MEDIUMTEXT COMMENT '估价结果（JSON）',
+    error TEXT COMMENT '估价失败的原因',
+    updated_at DATETIME NOT NULL COMMENT '最后更新时间',
+    UNIQUE KEY uk_job_position (job_id, position),
+    INDEX idx_job_status (job_id, status),
+    CONSTRAINT fk_job_items_job FOREIGN KEY (job_id) REFERENCES estimation_jobs (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
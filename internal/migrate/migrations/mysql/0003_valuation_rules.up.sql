@@ -0,0 +1,35 @@
+-- 动态属性估价规则，替代代码中硬编码的分档阈值
+CREATE TABLE IF NOT EXISTS valuation_rules (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    attribute_key VARCHAR(100) NOT NULL COMMENT '动态属性键，以*结尾表示前缀匹配',
+    operator VARCHAR(20) NOT NULL COMMENT '比较运算符',
+    compare_value VARCHAR(255) NOT NULL DEFAULT '' COMMENT '比较值',
+    min_value DOUBLE NULL COMMENT 'range运算的下限（含）',
+    max_value DOUBLE NULL COMMENT 'range运算的上限（不含）',
+    price_factor DECIMAL(10, 2) NOT NULL COMMENT '估价倍数',
+    grade_factor DECIMAL(10, 2) NOT NULL COMMENT '等级增量',
+    label VARCHAR(100) NOT NULL COMMENT '属性名称模板',
+    description VARCHAR(255) NOT NULL DEFAULT '' COMMENT '属性描述模板',
+    priority INT NOT NULL DEFAULT 0 COMMENT '优先级，数值小的先匹配',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
+    INDEX idx_attribute_key (attribute_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='动态属性估价规则表';
+
+-- 初始规则，与此前代码中的分档一致
+INSERT INTO valuation_rules (attribute_key, operator, compare_value, price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at) VALUES
+('alexa_rank', '<', '10000', 2.50, 0.80, 'Alexa排名优秀', 'Alexa 排名 {value}', 110, 1, NOW(), NOW()),
+('alexa_rank', '<', '100000', 1.80, 0.50, 'Alexa排名良好', 'Alexa 排名 {value}', 111, 1, NOW(), NOW()),
+('alexa_rank', '<', '1000000', 1.20, 0.20, 'Alexa排名一般', 'Alexa 排名 {value}', 112, 1, NOW(), NOW()),
+('alexa_rank', 'exists', '', 1.00, 0.00, 'Alexa排名', 'Alexa 排名 {value}', 113, 1, NOW(), NOW()),
+('search_volume', '>', '10000', 3.00, 0.90, '搜索量巨大', '搜索量 {value}', 120, 1, NOW(), NOW()),
+('search_volume', '>', '5000', 2.20, 0.70, '搜索量很高', '搜索量 {value}', 121, 1, NOW(), NOW()),
+('search_volume', '>', '1000', 1.80, 0.60, '搜索量较高', '搜索量 {value}', 122, 1, NOW(), NOW()),
+('search_volume', 'exists', '', 1.00, 0.00, '搜索量', '搜索量 {value}', 123, 1, NOW(), NOW()),
+('related_domain_*', 'contains', '未注册', 0.65, -0.20, '{suffix}相关域名未注册', '{name}.{suffix} 未注册', 130, 1, NOW(), NOW()),
+('related_domain_*', 'exists', '', 0.86, -0.10, '{suffix}相关域名已注册', '{name}.{suffix} {value}', 131, 1, NOW(), NOW()),
+('tieba_posts', '>', '10000', 2.25, 0.60, '贴吧数量巨大', '贴吧数量 {value}', 140, 1, NOW(), NOW()),
+('tieba_posts', '>', '0', 1.50, 0.30, '贴吧数量', '贴吧数量 {value}', 141, 1, NOW(), NOW()),
+('taobao_products', '>', '1000', 1.50, 0.30, '淘宝商品数量多', '淘宝商品 {value}', 150, 1, NOW(), NOW()),
+('taobao_products', '>', '0', 1.18, 0.10, '淘宝商品', '淘宝商品 {value}', 151, 1, NOW(), NOW());
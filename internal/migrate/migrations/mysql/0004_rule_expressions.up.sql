@@ -0,0 +1,3 @@
+-- 为估价规则增加条件表达式，operator 为 expr 时使用
+ALTER TABLE valuation_rules
+    ADD COLUMN expression VARCHAR(2000) NOT NULL DEFAULT '' COMMENT '条件表达式，operator为expr时使用' AFTER max_value;
@@ -0,0 +1,2 @@
+ALTER TABLE history_records DROP COLUMN rule_set_version;
+DROP TABLE IF EXISTS rule_sets;
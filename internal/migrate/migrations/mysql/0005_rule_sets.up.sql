@@ -0,0 +1,14 @@
+-- 规则集版本：域名属性、估价规则和估价基数的不可变快照
+CREATE TABLE IF NOT EXISTS rule_sets (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT '规则集版本号',
+    checksum CHAR(64) NOT NULL COMMENT '规则内容的SHA-256校验和',
+    author VARCHAR(100) NOT NULL COMMENT '创建者',
+    comment VARCHAR(255) NOT NULL DEFAULT '' COMMENT '变更说明',
+    content LONGTEXT NOT NULL COMMENT '规则内容（JSON）',
+    created_at DATETIME NOT NULL COMMENT '创建时间',
+    UNIQUE KEY uk_checksum (checksum)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='规则集版本表';
+
+-- 查询历史记录估价时使用的规则集版本，迁移前的记录为空
+ALTER TABLE history_records
+    ADD COLUMN rule_set_version BIGINT NULL COMMENT '估价使用的规则集版本' AFTER price;
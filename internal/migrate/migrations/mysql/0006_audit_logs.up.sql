@@ -0,0 +1,16 @@
+-- 审计日志：记录域名属性、估价规则和规则集的每次变更
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL COMMENT '变更对象类型：domain_attribute、valuation_rule、rule_set',
+    entity_id BIGINT NOT NULL COMMENT '变更对象ID，规则集为版本号',
+    entity_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT '变更对象名称，对象删除后仍可按名称查询',
+    action VARCHAR(16) NOT NULL COMMENT '操作：create、update、delete',
+    actor VARCHAR(100) NOT NULL COMMENT '操作者',
+    source VARCHAR(16) NOT NULL COMMENT '来源：admin、api、cli、system',
+    reason VARCHAR(500) NOT NULL DEFAULT '' COMMENT '变更原因',
+    before_value TEXT NULL COMMENT '变更前的值（JSON），新增时为空',
+    after_value TEXT NULL COMMENT '变更后的值（JSON），删除时为空',
+    created_at DATETIME NOT NULL COMMENT '变更时间',
+    INDEX idx_entity (entity_type, entity_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='审计日志表';
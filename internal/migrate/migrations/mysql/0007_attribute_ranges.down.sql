@@ -0,0 +1,4 @@
+DELETE FROM domain_attributes WHERE attribute_name = '1位长度' AND attribute_type = '基础属性' AND attribute_value = '1';
+ALTER TABLE domain_attributes
+    DROP COLUMN max_value,
+    DROP COLUMN min_value;
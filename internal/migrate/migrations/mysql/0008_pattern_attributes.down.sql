@@ -0,0 +1,3 @@
+DELETE FROM domain_attributes
+WHERE attribute_type = '基础属性' AND attribute_name LIKE '%形态'
+  AND attribute_value IN ('AAAA', '豹子号', 'AABB', 'ABAB', 'ABBA', 'ABC', '回文', '520', '1314', '含8', '含4');
@@ -0,0 +1,16 @@
+-- 号码形态属性：名称包含“形态”的基础属性，属性值为 internal/pattern 识别的形态
+INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, created_at, updated_at) VALUES
+-- 字形形态，一个域名最多属于其中一种
+('AAAA形态', '基础属性', 6.00, 1.2, 'AAAA', NOW(), NOW()),
+('豹子号形态', '基础属性', 3.50, 0.8, '豹子号', NOW(), NOW()),
+('AABB形态', '基础属性', 2.20, 0.5, 'AABB', NOW(), NOW()),
+('ABAB形态', '基础属性', 2.00, 0.45, 'ABAB', NOW(), NOW()),
+('ABBA形态', '基础属性', 1.80, 0.4, 'ABBA', NOW(), NOW()),
+('顺子形态', '基础属性', 2.50, 0.5, 'ABC', NOW(), NOW()),
+('回文形态', '基础属性', 1.50, 0.3, '回文', NOW(), NOW()),
+
+-- 数字寓意
+('520形态', '基础属性', 1.60, 0.3, '520', NOW(), NOW()),
+('1314形态', '基础属性', 1.60, 0.3, '1314', NOW(), NOW()),
+('吉利数字8形态', '基础属性', 1.20, 0.1, '含8', NOW(), NOW()),
+('数字4形态', '基础属性', 0.80, -0.2, '含4', NOW(), NOW());
@@ -0,0 +1,6 @@
+DELETE FROM domain_attributes
+WHERE attribute_type = '基础属性' AND attribute_name IN ('单拼形态', '双拼形态', '三拼形态')
+  AND attribute_value IN ('单拼', '双拼', '三拼');
+
+UPDATE domain_attributes SET attribute_name = '声母属性', attribute_type = '其他属性'
+WHERE attribute_name = '声母形态' AND attribute_type = '基础属性' AND attribute_value = '声母';
@@ -0,0 +1,9 @@
+-- 声母属性此前只能按名称包含“声母”的方式匹配，改为按拼音分析结果匹配的形态属性
+UPDATE domain_attributes SET attribute_name = '声母形态', attribute_type = '基础属性'
+WHERE attribute_name = '声母属性' AND attribute_type = '其他属性' AND attribute_value = '声母';
+
+-- 全拼按音节数区分的形态属性
+INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, created_at, updated_at) VALUES
+('单拼形态', '基础属性', 1.80, 0.4, '单拼', NOW(), NOW()),
+('双拼形态', '基础属性', 1.50, 0.3, '双拼', NOW(), NOW()),
+('三拼形态', '基础属性', 1.10, 0.1, '三拼', NOW(), NOW());
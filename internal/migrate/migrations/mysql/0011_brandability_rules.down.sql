@@ -0,0 +1 @@
+DELETE FROM valuation_rules WHERE attribute_key = 'brandability';
@@ -0,0 +1,6 @@
+-- 按 brandability 数据源的可品牌化评分（0到100）估价：易读易记的自造词有加成，难以发音的字母串降低倍数
+INSERT INTO valuation_rules (attribute_key, operator, compare_value, price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at) VALUES
+('brandability', '>=', '80', 1.60, 0.40, '极易品牌化', '可品牌化评分 {value}', 180, 1, NOW(), NOW()),
+('brandability', '>=', '65', 1.30, 0.20, '易于品牌化', '可品牌化评分 {value}', 181, 1, NOW(), NOW()),
+('brandability', '<', '35', 0.80, -0.20, '难以发音', '可品牌化评分 {value}', 182, 1, NOW(), NOW()),
+('brandability', 'exists', '', 1.00, 0.00, '可品牌化评分', '可品牌化评分 {value}', 183, 1, NOW(), NOW());
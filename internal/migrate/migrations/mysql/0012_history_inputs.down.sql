@@ -0,0 +1 @@
+ALTER TABLE history_records DROP COLUMN inputs;
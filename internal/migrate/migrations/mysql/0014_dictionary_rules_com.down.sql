@@ -0,0 +1,10 @@
+UPDATE valuation_rules SET expression = 'dict_hit && word_rank <= 300'
+WHERE operator = 'expr' AND attribute_key = 'word_rank' AND expression = 'tld == "com" && dict_hit && word_rank <= 300';
+UPDATE valuation_rules SET expression = 'dict_hit'
+WHERE operator = 'expr' AND attribute_key = 'word_rank' AND expression = 'tld == "com" && dict_hit';
+UPDATE valuation_rules SET expression = 'word_count == 2'
+WHERE operator = 'expr' AND attribute_key = 'words' AND expression = 'tld == "com" && word_count == 2';
+UPDATE valuation_rules SET expression = 'word_count >= 3'
+WHERE operator = 'expr' AND attribute_key = 'words' AND expression = 'tld == "com" && word_count >= 3';
+UPDATE valuation_rules SET expression = 'dict_hit && word_pos == "n"'
+WHERE operator = 'expr' AND attribute_key = 'word_pos' AND expression = 'tld == "com" && dict_hit && word_pos == "n"';
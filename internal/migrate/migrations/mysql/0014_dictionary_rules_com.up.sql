@@ -0,0 +1,11 @@
+-- 英文单词的加成只适用于 .com 域名，其他后缀的单词域名不按词典规则加价
+UPDATE valuation_rules SET expression = 'tld == "com" && dict_hit && word_rank <= 300'
+WHERE operator = 'expr' AND attribute_key = 'word_rank' AND expression = 'dict_hit && word_rank <= 300';
+UPDATE valuation_rules SET expression = 'tld == "com" && dict_hit'
+WHERE operator = 'expr' AND attribute_key = 'word_rank' AND expression = 'dict_hit';
+UPDATE valuation_rules SET expression = 'tld == "com" && word_count == 2'
+WHERE operator = 'expr' AND attribute_key = 'words' AND expression = 'word_count == 2';
+UPDATE valuation_rules SET expression = 'tld == "com" && word_count >= 3'
+WHERE operator = 'expr' AND attribute_key = 'words' AND expression = 'word_count >= 3';
+UPDATE valuation_rules SET expression = 'tld == "com" && dict_hit && word_pos == "n"'
+WHERE operator = 'expr' AND attribute_key = 'word_pos' AND expression = 'dict_hit && word_pos == "n"';
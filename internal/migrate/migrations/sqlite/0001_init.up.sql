@@ -0,0 +1,88 @@
+-- 初始表结构和属性数据，与 MySQL 的 0001_init 一致
+
+-- 域名基本信息表
+CREATE TABLE IF NOT EXISTS domains (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,          -- 完整域名
+    tld TEXT NOT NULL,                  -- 顶级域名
+    length INTEGER NOT NULL,            -- 域名长度（不含TLD）
+    structure TEXT NOT NULL,            -- 域名结构
+    register_date DATETIME,             -- 注册日期
+    expire_date DATETIME,               -- 到期日期
+    created_at DATETIME NOT NULL,       -- 记录创建时间
+    updated_at DATETIME NOT NULL        -- 记录更新时间
+);
+
+-- 域名属性表
+CREATE TABLE IF NOT EXISTS domain_attributes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attribute_name TEXT NOT NULL,       -- 属性名称
+    attribute_type TEXT NOT NULL,       -- 属性类型
+    price_factor REAL NOT NULL,         -- 估价倍数
+    grade_factor REAL NOT NULL,         -- 等级增量
+    attribute_value TEXT NOT NULL,      -- 属性值
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_attribute_type ON domain_attributes (attribute_type);
+CREATE INDEX IF NOT EXISTS idx_attribute_name ON domain_attributes (attribute_name);
+
+-- 查询历史记录表
+CREATE TABLE IF NOT EXISTS history_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    domain TEXT NOT NULL,               -- 查询的域名
+    grade REAL NOT NULL,                -- 品相等级
+    price REAL NOT NULL,                -- 估价结果
+    estimation_date DATETIME NOT NULL   -- 查询时间
+);
+CREATE INDEX IF NOT EXISTS idx_domain ON history_records (domain);
+CREATE INDEX IF NOT EXISTS idx_estimation_date ON history_records (estimation_date);
+
+-- 插入基础属性数据
+INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, created_at, updated_at) VALUES
+-- TLD属性
+('com后缀', '基础属性', 9.55, 0.5, 'com', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('net后缀', '基础属性', 2.38, 0.2, 'net', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('org后缀', '基础属性', 1.90, 0.1, 'org', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('cn后缀', '基础属性', 1.45, 0.0, 'cn', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('com.cn后缀', '基础属性', 1.20, -0.1, 'com.cn', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('cc后缀', '基础属性', 1.15, -0.2, 'cc', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('co后缀', '基础属性', 1.10, -0.2, 'co', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('io后缀', '基础属性', 1.80, 0.1, 'io', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('ai后缀', '基础属性', 2.50, 0.3, 'ai', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('中国后缀', '基础属性', 1.10, -0.1, '中国', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('公司后缀', '基础属性', 0.90, -0.2, '公司', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+
+-- 长度属性
+('2位长度', '基础属性', 8.50, 1.0, '2', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('3位长度', '基础属性', 5.20, 0.8, '3', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('4位长度', '基础属性', 3.60, 0.62, '4', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('5位长度', '基础属性', 2.10, 0.4, '5', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('6位长度', '基础属性', 1.50, 0.2, '6', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('7位长度', '基础属性', 1.20, 0.1, '7', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('8位长度', '基础属性', 1.10, 0.05, '8', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('9位及以上长度', '基础属性', 1.00, 0.0, '9', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+
+-- 结构属性
+('纯数字结构', '基础属性', 1.80, 0.5, '纯数字', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('纯字母结构', '基础属性', 1.26, 0.31, '纯字母', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('数字字母混合结构', '基础属性', 1.15, 0.2, '数字字母混合', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('含连字符结构', '基础属性', 0.85, -0.1, '含连字符', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('纯汉字结构', '基础属性', 1.60, 0.4, '纯汉字', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('汉字字母混合结构', '基础属性', 0.95, 0.0, '汉字字母混合', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('汉字数字混合结构', '基础属性', 1.00, 0.05, '汉字数字混合', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('汉字字母数字混合结构', '基础属性', 0.85, -0.1, '汉字字母数字混合', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('其他结构', '基础属性', 0.75, -0.2, '其他', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+
+-- 其他属性（示例）
+('声母属性', '其他属性', 0.85, 0.0, '声母', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('Alexa排名', '其他属性', 1.00, 0.0, 'Alexa', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('相关域名未注册', '其他属性', 0.65, -0.1, '未注册', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('搜索量', '其他属性', 1.80, 0.6, '搜索量', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('贴吧数量', '其他属性', 2.25, 0.6, '贴吧', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('百科系数', '其他属性', 1.30, 0.3, '百科', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('词典记录', '其他属性', 1.35, 0.3, '词典', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('360搜索指数', '其他属性', 1.00, 0.0, '360搜索', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('传媒系数', '其他属性', 3.70, 0.9, '传媒', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('社交系数', '其他属性', 1.00, 0.0, '社交', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('淘宝商品数量', '其他属性', 1.18, 0.1, '淘宝', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
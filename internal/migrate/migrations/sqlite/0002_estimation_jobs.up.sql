@@ -0,0 +1,29 @@
+-- 异步批量估价任务
+CREATE TABLE IF NOT EXISTS estimation_jobs (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,               -- 任务状态
+    total INTEGER NOT NULL,             -- 域名总数
+    done INTEGER NOT NULL DEFAULT 0,    -- 估价成功的数量
+    failed INTEGER NOT NULL DEFAULT 0,  -- 估价失败的数量
+    error TEXT,                         -- 任务失败的原因
+    created_at DATETIME NOT NULL,       -- 创建时间
+    updated_at DATETIME NOT NULL,       -- 最后更新时间
+    finished_at DATETIME                -- 结束时间
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimation_jobs_status ON estimation_jobs (status);
+
+-- 估价任务中的域名及结果
+CREATE TABLE IF NOT EXISTS estimation_job_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL REFERENCES estimation_jobs (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,          -- 在提交列表中的位置
+    domain TEXT NOT NULL,               -- 提交的域名
+    status TEXT NOT NULL,               -- 估价状态
+    result TEXT,                        -- 估价结果（JSON）
+    error TEXT,                         -- 估价失败的原因
+    updated_at DATETIME NOT NULL,       -- 最后更新时间
+    UNIQUE (job_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_estimation_job_items_status ON estimation_job_items (job_id, status);
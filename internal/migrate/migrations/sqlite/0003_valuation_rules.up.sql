@@ -0,0 +1,36 @@
+-- 动态属性估价规则，替代代码中硬编码的分档阈值
+CREATE TABLE IF NOT EXISTS valuation_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attribute_key TEXT NOT NULL,            -- 动态属性键，以*结尾表示前缀匹配
+    operator TEXT NOT NULL,                 -- 比较运算符
+    compare_value TEXT NOT NULL DEFAULT '', -- 比较值
+    min_value REAL,                         -- range运算的下限（含）
+    max_value REAL,                         -- range运算的上限（不含）
+    price_factor REAL NOT NULL,             -- 估价倍数
+    grade_factor REAL NOT NULL,             -- 等级增量
+    label TEXT NOT NULL,                    -- 属性名称模板
+    description TEXT NOT NULL DEFAULT '',   -- 属性描述模板
+    priority INTEGER NOT NULL DEFAULT 0,    -- 优先级，数值小的先匹配
+    enabled INTEGER NOT NULL DEFAULT 1,     -- 是否启用
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_valuation_rules_attribute_key ON valuation_rules (attribute_key);
+
+-- 初始规则，与此前代码中的分档一致
+INSERT INTO valuation_rules (attribute_key, operator, compare_value, price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at) VALUES
+('alexa_rank', '<', '10000', 2.50, 0.80, 'Alexa排名优秀', 'Alexa 排名 {value}', 110, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('alexa_rank', '<', '100000', 1.80, 0.50, 'Alexa排名良好', 'Alexa 排名 {value}', 111, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('alexa_rank', '<', '1000000', 1.20, 0.20, 'Alexa排名一般', 'Alexa 排名 {value}', 112, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('alexa_rank', 'exists', '', 1.00, 0.00, 'Alexa排名', 'Alexa 排名 {value}', 113, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('search_volume', '>', '10000', 3.00, 0.90, '搜索量巨大', '搜索量 {value}', 120, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('search_volume', '>', '5000', 2.20, 0.70, '搜索量很高', '搜索量 {value}', 121, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('search_volume', '>', '1000', 1.80, 0.60, '搜索量较高', '搜索量 {value}', 122, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('search_volume', 'exists', '', 1.00, 0.00, '搜索量', '搜索量 {value}', 123, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('related_domain_*', 'contains', '未注册', 0.65, -0.20, '{suffix}相关域名未注册', '{name}.{suffix} 未注册', 130, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('related_domain_*', 'exists', '', 0.86, -0.10, '{suffix}相关域名已注册', '{name}.{suffix} {value}', 131, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('tieba_posts', '>', '10000', 2.25, 0.60, '贴吧数量巨大', '贴吧数量 {value}', 140, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('tieba_posts', '>', '0', 1.50, 0.30, '贴吧数量', '贴吧数量 {value}', 141, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('taobao_products', '>', '1000', 1.50, 0.30, '淘宝商品数量多', '淘宝商品 {value}', 150, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('taobao_products', '>', '0', 1.18, 0.10, '淘宝商品', '淘宝商品 {value}', 151, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
@@ -0,0 +1,2 @@
+DELETE FROM valuation_rules WHERE operator = 'expr';
+ALTER TABLE valuation_rules DROP COLUMN expression;
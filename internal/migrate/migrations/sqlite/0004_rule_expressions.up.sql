@@ -0,0 +1,2 @@
+-- 为估价规则增加条件表达式，operator 为 expr 时使用
+ALTER TABLE valuation_rules ADD COLUMN expression TEXT NOT NULL DEFAULT ''; -- 条件表达式
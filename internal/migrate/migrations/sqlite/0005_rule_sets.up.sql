@@ -0,0 +1,12 @@
+-- 规则集版本：域名属性、估价规则和估价基数的不可变快照
+CREATE TABLE IF NOT EXISTS rule_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT, -- 规则集版本号
+    checksum TEXT NOT NULL UNIQUE,        -- 规则内容的SHA-256校验和
+    author TEXT NOT NULL,                 -- 创建者
+    comment TEXT NOT NULL DEFAULT '',     -- 变更说明
+    content TEXT NOT NULL,                -- 规则内容（JSON）
+    created_at DATETIME NOT NULL          -- 创建时间
+);
+
+-- 查询历史记录估价时使用的规则集版本，迁移前的记录为空
+ALTER TABLE history_records ADD COLUMN rule_set_version INTEGER; -- 估价使用的规则集版本
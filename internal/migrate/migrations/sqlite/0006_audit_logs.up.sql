@@ -0,0 +1,17 @@
+-- 审计日志：记录域名属性、估价规则和规则集的每次变更
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL,            -- 变更对象类型：domain_attribute、valuation_rule、rule_set
+    entity_id INTEGER NOT NULL,           -- 变更对象ID，规则集为版本号
+    entity_name TEXT NOT NULL DEFAULT '', -- 变更对象名称，对象删除后仍可按名称查询
+    action TEXT NOT NULL,                 -- 操作：create、update、delete
+    actor TEXT NOT NULL,                  -- 操作者
+    source TEXT NOT NULL,                 -- 来源：admin、api、cli、system
+    reason TEXT NOT NULL DEFAULT '',      -- 变更原因
+    before_value TEXT,                    -- 变更前的值（JSON），新增时为空
+    after_value TEXT,                     -- 变更后的值（JSON），删除时为空
+    created_at DATETIME NOT NULL          -- 变更时间
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
@@ -0,0 +1,15 @@
+-- 为域名属性增加取值范围，数值属性（如长度）按 [min_value, max_value) 匹配，为空表示不限
+ALTER TABLE domain_attributes ADD COLUMN min_value REAL; -- 取值范围的下限（含）
+ALTER TABLE domain_attributes ADD COLUMN max_value REAL; -- 取值范围的上限（不含）
+
+-- 9位及以上长度此前只匹配9位，改为不设上限
+UPDATE domain_attributes SET min_value = 9
+WHERE attribute_name = '9位及以上长度' AND attribute_type = '基础属性' AND attribute_value = '9';
+
+-- 增加1位长度，使每个长度都有对应的长度属性
+INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, min_value, max_value, created_at, updated_at)
+SELECT '1位长度', '基础属性', 12.00, 1.2, '1', 1, 2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+WHERE NOT EXISTS (
+    SELECT 1 FROM domain_attributes
+    WHERE attribute_type = '基础属性' AND attribute_name LIKE '%长度%' AND attribute_value = '1'
+);
@@ -0,0 +1 @@
+DELETE FROM valuation_rules WHERE operator = 'expr' AND attribute_key IN ('word_rank', 'words', 'word_pos');
@@ -0,0 +1,7 @@
+-- 按 dictionary 数据源切分出的英文单词估价：单个单词高于多词组合，随机字母串没有加成
+INSERT INTO valuation_rules (attribute_key, operator, compare_value, expression, price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at) VALUES
+('word_rank', 'expr', '', 'dict_hit && word_rank <= 300', 3.00, 0.80, '常用英文单词', '{name} 词频排名 {value}', 160, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('word_rank', 'expr', '', 'dict_hit', 2.00, 0.50, '英文单词', '{name} 词频排名 {value}', 161, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('words', 'expr', '', 'word_count == 2', 1.40, 0.30, '双词组合', '{value}', 170, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('words', 'expr', '', 'word_count >= 3', 1.05, 0.05, '多词组合', '{value}', 171, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('word_pos', 'expr', '', 'dict_hit && word_pos == "n"', 1.10, 0.10, '名词', '{name} 为名词', 175, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
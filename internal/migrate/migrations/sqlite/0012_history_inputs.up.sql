@@ -0,0 +1,2 @@
+-- 查询历史记录估价时使用的动态属性和参照时间（JSON），按原始规则集重新估价时用于复现记录的结果，迁移前的记录为空
+ALTER TABLE history_records ADD COLUMN inputs TEXT; -- 估价输入（JSON）
@@ -0,0 +1,2 @@
+DELETE FROM domain_attributes
+WHERE attribute_type = '基础属性' AND attribute_name = '多拼形态' AND attribute_value = '多拼';
@@ -0,0 +1,3 @@
+-- 4个及以上音节的全拼（如 zhongguorenmin）较长且不易记忆，补充此前缺少的多拼形态属性
+INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, created_at, updated_at) VALUES
+('多拼形态', '基础属性', 0.90, -0.1, '多拼', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
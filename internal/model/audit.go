@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计日志的变更对象类型
+const (
+	AuditEntityAttribute = "domain_attribute" // 域名属性
+	AuditEntityRule      = "valuation_rule"   // 动态属性估价规则
+	AuditEntityRuleSet   = "rule_set"         // 规则集版本
+)
+
+// 审计日志的操作类型
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// 变更的来源
+const (
+	AuditSourceAdmin  = "admin"  // 管理后台
+	AuditSourceAPI    = "api"    // API接口
+	AuditSourceCLI    = "cli"    // 命令行
+	AuditSourceSystem = "system" // 系统自动产生，如检测到数据库中的规则被直接修改
+)
+
+// AuditLog 表示一次规则或配置变更的审计记录
+type AuditLog struct {
+	ID         int64           `json:"id"`
+	EntityType string          `json:"entityType"`       // 变更对象类型
+	EntityID   int64           `json:"entityId"`         // 变更对象ID，规则集为版本号
+	EntityName string          `json:"entityName"`       // 变更对象名称，如 com后缀
+	Action     string          `json:"action"`           // 操作类型
+	Actor      string          `json:"actor"`            // 操作者
+	Source     string          `json:"source"`           // 变更来源
+	Reason     string          `json:"reason"`           // 变更原因
+	Before     json.RawMessage `json:"before,omitempty"` // 变更前的值，新增时为空
+	After      json.RawMessage `json:"after,omitempty"`  // 变更后的值，删除时为空
+	CreatedAt  time.Time       `json:"createdAt"`        // 变更时间
+}
+
+// AuditFilter 表示审计日志的查询条件，零值字段不参与筛选
+type AuditFilter struct {
+	EntityType string    // 变更对象类型
+	EntityID   int64     // 变更对象ID
+	EntityName string    // 变更对象名称，模糊匹配
+	From       time.Time // 起始时间（含）
+	To         time.Time // 截止时间（不含）
+	Limit      int       // 最多返回的条数
+}
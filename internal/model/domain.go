@@ -7,34 +7,86 @@ import (
 // Domain 表示域名及其属性
 type Domain struct {
 	ID           int64     `json:"id"`
-	Name         string    `json:"name"`         // 域名名称，如 aqzt.com
-	TLD          string    `json:"tld"`          // 顶级域名，如 com
-	Length       int       `json:"length"`       // 域名长度（不含TLD）
+	Name         string    `json:"name"`         // 域名名称（规范化的小写punycode形式），如 aqzt.com、xn--fiq228c.com
+	UnicodeName  string    `json:"unicodeName"`  // 域名的Unicode形式，如 中文.com
+	TLD          string    `json:"tld"`          // 有效顶级域名（公共后缀），如 com、com.cn
+	Subdomain    string    `json:"subdomain"`    // 子域名，如 www
+	Length       int       `json:"length"`       // 域名长度（不含TLD，按Unicode字符计算）
 	Structure    string    `json:"structure"`    // 域名结构，如 纯字母、数字字母混合等
+	Patterns     []string  `json:"patterns"`     // 号码形态，如 AABB、豹子号、520
+	Syllables    []string  `json:"syllables"`    // 全拼主体切分后的拼音音节，如 [tao bao]，不是全拼时为空
 	RegisterDate time.Time `json:"registerDate"` // 注册日期
 	ExpireDate   time.Time `json:"expireDate"`   // 到期日期
 	CreatedAt    time.Time `json:"createdAt"`    // 记录创建时间
 	UpdatedAt    time.Time `json:"updatedAt"`    // 记录更新时间
 }
 
+// 域名属性的类型
+const (
+	AttributeTypeBase  = "基础属性" // TLD、长度和结构属性
+	AttributeTypeOther = "其他属性" // 没有匹配到动态属性时使用的静态属性
+)
+
 // DomainAttribute 表示域名的各种属性及其对估价的影响
 type DomainAttribute struct {
-	ID             int64   `json:"id"`
-	AttributeName  string  `json:"attributeName"`  // 属性名称，如 "com后缀"
-	AttributeType  string  `json:"attributeType"`  // 属性类型，如 "基础属性" 或 "其他属性"
-	PriceFactor    float64 `json:"priceFactor"`    // 估价倍数，如 9.55
-	GradeFactor    float64 `json:"gradeFactor"`    // 等级增量，如 0.5
-	AttributeValue string  `json:"attributeValue"` // 属性值，如 "com"
+	ID             int64    `json:"id"`
+	AttributeName  string   `json:"attributeName"`      // 属性名称，如 "com后缀"
+	AttributeType  string   `json:"attributeType"`      // 属性类型，如 "基础属性" 或 "其他属性"
+	PriceFactor    float64  `json:"priceFactor"`        // 估价倍数，如 9.55
+	GradeFactor    float64  `json:"gradeFactor"`        // 等级增量，如 0.5
+	AttributeValue string   `json:"attributeValue"`     // 属性值，如 "com"
+	MinValue       *float64 `json:"minValue,omitempty"` // 数值属性（如长度）取值范围的下限（含），为空表示不限
+	MaxValue       *float64 `json:"maxValue,omitempty"` // 数值属性取值范围的上限（不含），为空表示不限
+}
+
+// HasRange 判断属性是否设置了取值范围
+func (a DomainAttribute) HasRange() bool {
+	return a.MinValue != nil || a.MaxValue != nil
+}
+
+// InRange 判断数值是否在属性的取值范围内
+func (a DomainAttribute) InRange(v float64) bool {
+	return (a.MinValue == nil || v >= *a.MinValue) && (a.MaxValue == nil || v < *a.MaxValue)
+}
+
+// Equal 判断两个属性的所有字段是否相同，取值范围按数值比较
+func (a DomainAttribute) Equal(b DomainAttribute) bool {
+	return a.ID == b.ID && a.AttributeName == b.AttributeName && a.AttributeType == b.AttributeType &&
+		a.PriceFactor == b.PriceFactor && a.GradeFactor == b.GradeFactor && a.AttributeValue == b.AttributeValue &&
+		equalBound(a.MinValue, b.MinValue) && equalBound(a.MaxValue, b.MaxValue)
+}
+
+// equalBound 比较两个可选的取值范围边界
+func equalBound(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
 // EstimationResult 表示域名估价结果
 type EstimationResult struct {
-	Domain            string              `json:"domain"`            // 域名
-	Grade             float64             `json:"grade"`             // 品相等级
-	Price             float64             `json:"price"`             // 保守估价
-	BaseAttributes    []AttributeDetail   `json:"baseAttributes"`    // 基础属性详情
-	OtherAttributes   []AttributeDetail   `json:"otherAttributes"`   // 其他属性详情
-	EstimationDate    time.Time           `json:"estimationDate"`    // 估价日期
+	Domain          string            `json:"domain"`          // 域名
+	Grade           float64           `json:"grade"`           // 品相等级
+	Price           float64           `json:"price"`           // 保守估价
+	BaseAttributes  []AttributeDetail `json:"baseAttributes"`  // 基础属性详情
+	OtherAttributes []AttributeDetail `json:"otherAttributes"` // 其他属性详情
+	RuleSetVersion  int64             `json:"ruleSetVersion"`  // 估价使用的规则集版本
+	EstimationDate  time.Time         `json:"estimationDate"`  // 估价日期
+	Inputs          *EstimationInputs `json:"-"`               // 估价使用的外部输入，随历史记录保存
+}
+
+// EstimationInputs 记录估价时使用的外部输入，按原始规则集重新估价时使用这些输入以复现记录的结果
+type EstimationInputs struct {
+	DynamicAttributes map[string]interface{} `json:"dynamicAttributes"` // 动态属性
+	ReferenceDate     time.Time              `json:"referenceDate"`     // 默认注册日期和注册年数、到期天数的参照时间
+}
+
+// BatchEstimationItem 表示批量估价中单个域名的结果
+type BatchEstimationItem struct {
+	Domain string            `json:"domain"`           // 输入的域名
+	Result *EstimationResult `json:"result,omitempty"` // 估价结果，失败时为空
+	Error  string            `json:"error,omitempty"`  // 估价失败的原因
 }
 
 // AttributeDetail 表示属性详情
@@ -48,9 +100,18 @@ type AttributeDetail struct {
 
 // HistoryRecord 表示查询历史记录
 type HistoryRecord struct {
-	ID             int64     `json:"id"`
-	Domain         string    `json:"domain"`         // 查询的域名
-	Grade          float64   `json:"grade"`          // 品相等级
-	Price          float64   `json:"price"`          // 估价结果
-	EstimationDate time.Time `json:"estimationDate"` // 查询时间
+	ID             int64             `json:"id"`
+	Domain         string            `json:"domain"`           // 查询的域名
+	Grade          float64           `json:"grade"`            // 品相等级
+	Price          float64           `json:"price"`            // 估价结果
+	RuleSetVersion int64             `json:"ruleSetVersion"`   // 估价使用的规则集版本，0表示规则集版本化之前的记录
+	EstimationDate time.Time         `json:"estimationDate"`   // 查询时间
+	Inputs         *EstimationInputs `json:"inputs,omitempty"` // 估价使用的外部输入，保存输入之前的记录为空
+}
+
+// RerunResult 表示按规则集重新估价历史记录的结果
+type RerunResult struct {
+	Record   HistoryRecord     `json:"record"`             // 原始的历史记录
+	Original *EstimationResult `json:"original,omitempty"` // 按记录当时的规则集重新估价的结果
+	Current  *EstimationResult `json:"current,omitempty"`  // 按当前规则集重新估价的结果
 }
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// 估价任务状态
+const (
+	JobStatusPending   = "pending"   // 等待执行
+	JobStatusRunning   = "running"   // 执行中
+	JobStatusCompleted = "completed" // 已完成
+	JobStatusCancelled = "cancelled" // 已取消
+	JobStatusFailed    = "failed"    // 执行失败
+)
+
+// 估价任务中单个域名的状态
+const (
+	JobItemPending = "pending" // 未估价
+	JobItemDone    = "done"    // 估价成功
+	JobItemFailed  = "failed"  // 估价失败
+)
+
+// Job 表示一个异步批量估价任务
+type Job struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`               // 任务状态
+	Total      int        `json:"total"`                // 域名总数
+	Done       int        `json:"done"`                 // 估价成功的数量
+	Failed     int        `json:"failed"`               // 估价失败的数量
+	Error      string     `json:"error,omitempty"`      // 任务失败的原因
+	CreatedAt  time.Time  `json:"createdAt"`            // 创建时间
+	UpdatedAt  time.Time  `json:"updatedAt"`            // 最后更新时间
+	FinishedAt *time.Time `json:"finishedAt,omitempty"` // 结束时间
+}
+
+// Finished 判断任务是否已经结束
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusCancelled || j.Status == JobStatusFailed
+}
+
+// JobItem 表示估价任务中的单个域名
+type JobItem struct {
+	JobID    string            `json:"-"`
+	Position int               `json:"position"`         // 在提交列表中的位置，从0开始
+	Domain   string            `json:"domain"`           // 提交的域名
+	Status   string            `json:"status"`           // 估价状态
+	Result   *EstimationResult `json:"result,omitempty"` // 估价结果
+	Error    string            `json:"error,omitempty"`  // 估价失败的原因
+}
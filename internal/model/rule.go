@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// ValuationRule 表示一条动态属性估价规则
+// 同一属性键的多条规则按优先级依次匹配，第一条满足条件的规则生效，用于表达分档阈值
+type ValuationRule struct {
+	ID           int64     `json:"id"`
+	AttributeKey string    `json:"attributeKey"` // 动态属性键，如 alexa_rank；以 * 结尾表示前缀匹配，如 related_domain_*
+	Operator     string    `json:"operator"`     // 比较运算符，如 <、>=、range、contains、exists、expr
+	Value        string    `json:"value"`        // 比较值
+	MinValue     *float64  `json:"minValue"`     // range 运算的下限（含），为空表示不限
+	MaxValue     *float64  `json:"maxValue"`     // range 运算的上限（不含），为空表示不限
+	Expression   string    `json:"expression"`   // expr 运算的条件表达式，如 length <= 4 && tld in ["com", "net"]
+	PriceFactor  float64   `json:"priceFactor"`  // 估价倍数
+	GradeFactor  float64   `json:"gradeFactor"`  // 等级增量
+	Label        string    `json:"label"`        // 属性名称模板，如 {suffix}相关域名已注册
+	Description  string    `json:"description"`  // 属性描述模板，如 Alexa 排名 {value}
+	Priority     int       `json:"priority"`     // 优先级，数值小的先匹配
+	Enabled      bool      `json:"enabled"`      // 是否启用
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// RuleChanges 表示需要在同一事务中应用的一组域名属性和估价规则变更，如导入规则包
+type RuleChanges struct {
+	CreateAttributes []DomainAttribute
+	UpdateAttributes []DomainAttribute
+	DeleteAttributes []int64
+	CreateRules      []ValuationRule
+	UpdateRules      []ValuationRule
+	DeleteRules      []int64
+}
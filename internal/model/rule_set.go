@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// RuleSet 表示一个不可变的规则集版本
+// 每次估价都记录所使用的版本，规则修改后仍然可以按原来的规则解释和重现历史估价
+type RuleSet struct {
+	Version   int64           `json:"version"`           // 版本号
+	Checksum  string          `json:"checksum"`          // 规则内容的SHA-256校验和，内容相同的规则集只保存一次
+	Author    string          `json:"author"`            // 创建者，如管理员用户名；检测到直接修改数据库时为 system
+	Comment   string          `json:"comment"`           // 变更说明
+	CreatedAt time.Time       `json:"createdAt"`         // 创建时间
+	Content   *RuleSetContent `json:"content,omitempty"` // 规则内容，列表中不包含
+}
+
+// RuleSetContent 表示影响估价结果的全部规则
+type RuleSetContent struct {
+	BasePrice      float64           `json:"basePrice"`      // 估价基数
+	BaseGrade      float64           `json:"baseGrade"`      // 等级基数
+	Attributes     []DomainAttribute `json:"attributes"`     // 域名属性
+	ValuationRules []ValuationRule   `json:"valuationRules"` // 动态属性估价规则
+}
@@ -0,0 +1,136 @@
+// Package pattern 识别域名主体的号码形态，如 AABB、ABAB、豹子号、回文，以及 520、1314 等有寓意的数字
+//
+// 形态分为两类：字形形态按整个主体的字符排列判断，只适用于纯数字或纯字母的主体，
+// 一个主体最多属于其中一种；数字寓意按主体中出现的数字判断。不同类别的形态可以同时存在。
+// 双拼、声母等拼音形态由 internal/pinyin 识别。
+package pattern
+
+import (
+	"strings"
+)
+
+// 字形形态，按优先级排列，一个主体只取第一个符合的
+const (
+	AAAA       = "AAAA" // 4位及以上全部相同，如 8888、aaaaa
+	Leopard    = "豹子号"  // 3位全部相同，如 666
+	AABB       = "AABB" // 如 1122、aabb
+	ABAB       = "ABAB" // 两个字符交替出现且长度为4位及以上的偶数，如 1212、abab
+	ABBA       = "ABBA" // 如 1221、abba
+	Sequence   = "ABC"  // 3位及以上连续递增或递减，如 123、9876、abcd
+	Palindrome = "回文"   // 3位及以上正读反读相同且不属于以上形态，如 12321、aba
+)
+
+// 数字寓意
+const (
+	LoveYou  = "520"  // 包含 520，“我爱你”
+	Lifetime = "1314" // 包含 1314，“一生一世”
+	Lucky8   = "含8"   // 纯数字且包含 8
+	Unlucky4 = "含4"   // 纯数字且包含 4，不计 1314 中的 4
+)
+
+// Tags 列出所有形态，按 Classify 返回的顺序排列
+var Tags = []string{AAAA, Leopard, AABB, ABAB, ABBA, Sequence, Palindrome, LoveYou, Lifetime, Lucky8, Unlucky4}
+
+// Known 判断是否为已知的形态
+func Known(tag string) bool {
+	for _, t := range Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify 返回域名主体（不含后缀）的所有形态，没有时返回空切片
+// 字母不区分大小写
+func Classify(label string) []string {
+	label = strings.ToLower(label)
+	tags := []string{}
+
+	digits, letters := isAll(label, '0', '9'), isAll(label, 'a', 'z')
+	if digits || letters {
+		if shape := shapeOf(label); shape != "" {
+			tags = append(tags, shape)
+		}
+	}
+
+	if strings.Contains(label, "520") {
+		tags = append(tags, LoveYou)
+	}
+	if strings.Contains(label, "1314") {
+		tags = append(tags, Lifetime)
+	}
+	if digits {
+		if strings.Contains(label, "8") {
+			tags = append(tags, Lucky8)
+		}
+		if strings.Contains(strings.ReplaceAll(label, "1314", ""), "4") {
+			tags = append(tags, Unlucky4)
+		}
+	}
+	return tags
+}
+
+// shapeOf 返回纯数字或纯字母主体的字形形态，不符合任何形态时返回空字符串
+func shapeOf(s string) string {
+	n := len(s)
+	if n < 3 {
+		return ""
+	}
+
+	switch {
+	case strings.Count(s, s[:1]) == n:
+		if n >= 4 {
+			return AAAA
+		}
+		return Leopard
+	case n == 4 && s[0] == s[1] && s[2] == s[3]:
+		return AABB
+	case n%2 == 0 && s[0] != s[1] && strings.Repeat(s[:2], n/2) == s:
+		return ABAB
+	case n == 4 && s[0] == s[3] && s[1] == s[2]:
+		return ABBA
+	case isSequence(s):
+		return Sequence
+	case isPalindrome(s):
+		return Palindrome
+	}
+	return ""
+}
+
+// isAll 判断 s 不为空且所有字符都在 [lo, hi] 范围内
+func isAll(s string, lo, hi byte) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < lo || s[i] > hi {
+			return false
+		}
+	}
+	return true
+}
+
+// isSequence 判断 s 是否连续递增或递减，如 123、cba
+func isSequence(s string) bool {
+	step := int(s[1]) - int(s[0])
+	if step != 1 && step != -1 {
+		return false
+	}
+	for i := 2; i < len(s); i++ {
+		if int(s[i])-int(s[i-1]) != step {
+			return false
+		}
+	}
+	return true
+}
+
+// isPalindrome 判断 s 是否正读反读相同
+func isPalindrome(s string) bool {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		if s[i] != s[j] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package pattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		label string
+		want  []string
+	}{
+		// 字形形态
+		{"8888", []string{AAAA, Lucky8}},
+		{"AAAAA", []string{AAAA}},
+		{"666", []string{Leopard}},
+		{"aaa", []string{Leopard}},
+		{"1122", []string{AABB}},
+		{"aabb", []string{AABB}},
+		{"1212", []string{ABAB}},
+		{"ababab", []string{ABAB}},
+		{"1221", []string{ABBA}},
+		{"abba", []string{ABBA}},
+		{"123", []string{Sequence}},
+		{"9876", []string{Sequence, Lucky8}},
+		{"abcd", []string{Sequence}},
+		{"cba", []string{Sequence}},
+		{"12321", []string{Palindrome}},
+		{"aba", []string{Palindrome}},
+
+		// 数字寓意
+		{"520", []string{LoveYou}},
+		{"5201314", []string{LoveYou, Lifetime}},
+		{"1314", []string{Lifetime}},
+		{"13140", []string{Lifetime}},
+		{"13144", []string{Lifetime, Unlucky4}},
+		{"168", []string{Lucky8}},
+		{"1234", []string{Sequence, Unlucky4}},
+		{"a520", []string{LoveYou}},
+
+		// 不符合任何形态
+		{"abc1", []string{}},
+		{"a1a1", []string{}},
+		{"1a1", []string{}},
+		{"a8b4", []string{}},
+		{"12", []string{}},
+		{"ab", []string{}},
+		{"1357", []string{}},
+		{"google", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.label); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Classify(%q) = %v, want %v", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestKnown(t *testing.T) {
+	for _, tag := range Tags {
+		if !Known(tag) {
+			t.Errorf("Known(%q) = false", tag)
+		}
+	}
+	if Known("双拼") {
+		t.Error("Known(双拼) = true, 拼音形态由 pinyin 包识别")
+	}
+}
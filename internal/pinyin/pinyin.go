@@ -0,0 +1,143 @@
+// Package pinyin 识别由汉语拼音组成的字母域名，如全拼的 taobao、jiudian 和声母缩写的 bjdx
+//
+// 拼音不带声调，ü 按输入法习惯写作 v，如 lv、nve。
+package pinyin
+
+import (
+	"strings"
+)
+
+// syllableList 是普通话的全部无声调音节
+const syllableList = `
+a ai an ang ao
+ba bai ban bang bao bei ben beng bi bian biao bie bin bing bo bu
+ca cai can cang cao ce cen ceng cha chai chan chang chao che chen cheng chi chong chou chu chua chuai chuan chuang chui chun chuo ci cong cou cu cuan cui cun cuo
+da dai dan dang dao de dei den deng di dia dian diao die ding diu dong dou du duan dui dun duo
+e ei en eng er
+fa fan fang fei fen feng fo fou fu
+ga gai gan gang gao ge gei gen geng gong gou gu gua guai guan guang gui gun guo
+ha hai han hang hao he hei hen heng hong hou hu hua huai huan huang hui hun huo
+ji jia jian jiang jiao jie jin jing jiong jiu ju juan jue jun
+ka kai kan kang kao ke kei ken keng kong kou ku kua kuai kuan kuang kui kun kuo
+la lai lan lang lao le lei leng li lia lian liang liao lie lin ling liu lo long lou lu luan lue lun luo lv lve
+ma mai man mang mao me mei men meng mi mian miao mie min ming miu mo mou mu
+na nai nan nang nao ne nei nen neng ni nian niang niao nie nin ning niu nong nou nu nuan nue nuo nv nve
+o ou
+pa pai pan pang pao pei pen peng pi pian piao pie pin ping po pou pu
+qi qia qian qiang qiao qie qin qing qiong qiu qu quan que qun
+ran rang rao re ren reng ri rong rou ru rua ruan rui run ruo
+sa sai san sang sao se sen seng sha shai shan shang shao she shei shen sheng shi shou shu shua shuai shuan shuang shui shun shuo si song sou su suan sui sun suo
+ta tai tan tang tao te teng ti tian tiao tie ting tong tou tu tuan tui tun tuo
+wa wai wan wang wei wen weng wo wu
+xi xia xian xiang xiao xie xin xing xiong xiu xu xuan xue xun
+ya yan yang yao ye yi yin ying yo yong you yu yuan yue yun
+za zai zan zang zao ze zei zen zeng zha zhai zhan zhang zhao zhe zhei zhen zheng zhi zhong zhou zhu zhua zhuai zhuan zhuang zhui zhun zhuo zi zong zou zu zuan zui zun zuo
+`
+
+// 拼音形态，全拼按音节最少的切分方式计算音节数
+const (
+	Initials = "声母" // 全部由声母组成的缩写，如 bjdx
+	OneSyl   = "单拼" // 1个音节的全拼，如 xian
+	TwoSyl   = "双拼" // 2个音节的全拼，如 taobao
+	ThreeSyl = "三拼" // 3个音节的全拼，如 zhongguoren
+	ManySyl  = "多拼" // 4个及以上音节的全拼
+)
+
+// Tags 列出所有拼音形态
+var Tags = []string{Initials, OneSyl, TwoSyl, ThreeSyl, ManySyl}
+
+// Known 判断是否为已知的拼音形态
+func Known(tag string) bool {
+	for _, t := range Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify 返回纯字母主体的拼音形态，不是拼音时返回空字符串，字母不区分大小写
+func Classify(s string) string {
+	if IsInitials(s) {
+		return Initials
+	}
+	switch len(Segment(s)) {
+	case 0:
+		return ""
+	case 1:
+		return OneSyl
+	case 2:
+		return TwoSyl
+	case 3:
+		return ThreeSyl
+	}
+	return ManySyl
+}
+
+// 最长的音节为6个字母，如 zhuang
+const maxSyllableLength = 6
+
+// syllables 是音节的集合
+var syllables = func() map[string]bool {
+	set := make(map[string]bool)
+	for _, s := range strings.Fields(syllableList) {
+		set[s] = true
+	}
+	return set
+}()
+
+// IsSyllable 判断 s 是否为一个拼音音节
+func IsSyllable(s string) bool {
+	return syllables[s]
+}
+
+// Segment 将全拼字符串切分为音节，无法完整切分时返回 nil
+// 有多种切分方式时取音节最少的一种，音节数相同时前面的音节尽量长，如 xian -> [xian]、fangan -> [fang an]
+func Segment(s string) []string {
+	s = strings.ToLower(s)
+	n := len(s)
+	if n == 0 {
+		return nil
+	}
+
+	// best[i] 为 s[i:] 切分所需的最少音节数，-1 表示无法切分；next[i] 为第一个音节的结束位置
+	best := make([]int, n+1)
+	next := make([]int, n+1)
+	for i := 0; i < n; i++ {
+		best[i] = -1
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := min(n, i+maxSyllableLength); j > i; j-- {
+			if best[j] < 0 || !syllables[s[i:j]] {
+				continue
+			}
+			if best[i] < 0 || best[j]+1 < best[i] {
+				best[i], next[i] = best[j]+1, j
+			}
+		}
+	}
+	if best[0] < 0 {
+		return nil
+	}
+
+	parts := make([]string, 0, best[0])
+	for i := 0; i < n; i = next[i] {
+		parts = append(parts, s[i:next[i]])
+	}
+	return parts
+}
+
+// IsInitials 判断 s 是否全部由声母组成，如 bjdx（北京大学）
+// 声母缩写取每个字的第一个字母，zh、ch、sh 写作 z、c、s，y、w 也视为声母
+func IsInitials(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' || strings.IndexByte("aeiouv", c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package pinyin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"taobao", []string{"tao", "bao"}},
+		{"TaoBao", []string{"tao", "bao"}},
+		{"jiudian", []string{"jiu", "dian"}},
+		// 音节最少优先：xian 不切分为 xi an
+		{"xian", []string{"xian"}},
+		// 音节数相同时前面的音节尽量长：fang an 而不是 fan gan
+		{"fangan", []string{"fang", "an"}},
+		{"zhongguoren", []string{"zhong", "guo", "ren"}},
+		{"lvxing", []string{"lv", "xing"}},
+		{"google", nil},
+		{"bjdx", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Segment(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segment(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"bjdx", Initials},
+		{"BJDX", Initials},
+		{"xian", OneSyl},
+		{"taobao", TwoSyl},
+		{"jiudian", TwoSyl},
+		{"fangan", TwoSyl},
+		{"zhongguoren", ThreeSyl},
+		{"zhonghuarenmin", ManySyl},
+		{"google", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.input); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIsInitials(t *testing.T) {
+	tests := map[string]bool{
+		"bjdx":  true,
+		"zgyh":  true,
+		"ywx":   true,
+		"bjda":  false,
+		"lvbj":  false,
+		"bj1x":  false,
+		"bj-dx": false,
+		"":      false,
+	}
+	for input, want := range tests {
+		if got := IsInitials(input); got != want {
+			t.Errorf("IsInitials(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
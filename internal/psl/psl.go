@@ -0,0 +1,173 @@
+// Package psl 基于公共后缀列表（Public Suffix List）拆分域名
+//
+// 内置的列表来自 https://publicsuffix.org/list/public_suffix_list.dat，
+// 可以通过配置 domain.publicSuffixFile 指定更新后的列表文件替换。
+package psl
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+//go:embed public_suffix_list.dat
+var embeddedList []byte
+
+// List 表示解析后的公共后缀列表
+type List struct {
+	normal    map[string]bool // 普通规则，如 com.cn
+	wildcard  map[string]bool // 通配规则，如 *.ck（存储为 ck）
+	exception map[string]bool // 例外规则，如 !www.ck（存储为 www.ck）
+}
+
+// Parts 表示拆分后的域名各部分
+type Parts struct {
+	Subdomain string // 子域名，如 www
+	Label     string // 可注册部分的主体，如 abc
+	Suffix    string // 有效顶级域名（公共后缀），如 com.cn
+}
+
+// Domain 返回可注册域名，如 abc.com.cn
+func (p Parts) Domain() string {
+	return p.Label + "." + p.Suffix
+}
+
+var (
+	defaultOnce sync.Once
+	defaultList *List
+	defaultErr  error
+)
+
+// Default 返回内置的公共后缀列表（仅包含ICANN部分）
+func Default() (*List, error) {
+	defaultOnce.Do(func() {
+		defaultList, defaultErr = Parse(bytes.NewReader(embeddedList), false)
+	})
+	return defaultList, defaultErr
+}
+
+// Load 加载公共后缀列表，path 为空时返回内置列表
+// includePrivate 为 true 时同时包含私有域部分（如 github.io）
+func Load(path string, includePrivate bool) (*List, error) {
+	if path == "" {
+		if includePrivate {
+			return Parse(bytes.NewReader(embeddedList), true)
+		}
+		return Default()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开公共后缀列表失败: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(f, includePrivate)
+}
+
+// Parse 从 public_suffix_list.dat 格式的数据中解析后缀规则
+func Parse(r io.Reader, includePrivate bool) (*List, error) {
+	l := &List{
+		normal:    make(map[string]bool),
+		wildcard:  make(map[string]bool),
+		exception: make(map[string]bool),
+	}
+
+	private := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "//") {
+			if strings.Contains(line, "===BEGIN PRIVATE DOMAINS===") {
+				private = true
+			}
+			continue
+		}
+		if line == "" || (private && !includePrivate) {
+			continue
+		}
+
+		// 规则以第一个空白字符结束
+		if idx := strings.IndexAny(line, " \t"); idx != -1 {
+			line = line[:idx]
+		}
+		// 列表中的国际化后缀（如 中国）统一转换为punycode，与规范化后的域名比较
+		line = strings.ToLower(line)
+		if ascii, err := idna.ToASCII(line); err == nil {
+			line = ascii
+		}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			l.exception[line[1:]] = true
+		case strings.HasPrefix(line, "*."):
+			l.wildcard[line[2:]] = true
+		default:
+			l.normal[line] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取公共后缀列表失败: %w", err)
+	}
+	if len(l.normal) == 0 {
+		return nil, fmt.Errorf("公共后缀列表为空")
+	}
+
+	return l, nil
+}
+
+// PublicSuffix 返回域名的公共后缀，未收录的顶级域按默认规则 "*" 取最后一段
+// 国际化域名需先转换为punycode形式
+func (l *List) PublicSuffix(domain string) string {
+	labels := strings.Split(strings.ToLower(strings.Trim(domain, ".")), ".")
+
+	// 从最长的候选后缀开始匹配，第一个命中的即为最长匹配
+	for i := 0; i < len(labels); i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if l.exception[candidate] {
+			return strings.Join(labels[i+1:], ".")
+		}
+		if l.normal[candidate] {
+			return candidate
+		}
+		if i+1 < len(labels) && l.wildcard[strings.Join(labels[i+1:], ".")] {
+			return candidate
+		}
+	}
+
+	return labels[len(labels)-1]
+}
+
+// Split 将域名拆分为子域名、主体和公共后缀
+func (l *List) Split(domain string) (*Parts, error) {
+	domain = strings.Trim(domain, ".")
+	labels := strings.Split(domain, ".")
+	for _, label := range labels {
+		if label == "" {
+			return nil, fmt.Errorf("无效的域名格式: %s", domain)
+		}
+	}
+
+	suffix := l.PublicSuffix(domain)
+	suffixLabels := strings.Count(suffix, ".") + 1
+	if len(labels) <= suffixLabels {
+		return nil, fmt.Errorf("域名仅包含公共后缀: %s", domain)
+	}
+
+	// 保留输入中的原始大小写，由调用方负责规范化
+	n := len(labels) - suffixLabels
+	return &Parts{
+		Subdomain: strings.Join(labels[:n-1], "."),
+		Label:     labels[n-1],
+		Suffix:    strings.Join(labels[n:], "."),
+	}, nil
+}
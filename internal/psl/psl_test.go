@@ -0,0 +1,137 @@
+package psl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublicSuffix(t *testing.T) {
+	list, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"abc.com", "com"},
+		{"www.ABC.COM.", "com"},
+		{"abc.com.cn", "com.cn"},
+		{"www.abc.com.cn", "com.cn"},
+		{"abc.cn", "cn"},
+		{"abc.co.uk", "co.uk"},
+		// 通配规则 *.ck 和例外规则 !www.ck
+		{"abc.ck", "abc.ck"},
+		{"www.abc.ck", "abc.ck"},
+		{"www.ck", "ck"},
+		{"a.www.ck", "ck"},
+		// 国际化后缀按punycode形式匹配
+		{"xn--fiq228c.xn--fiqs8s", "xn--fiqs8s"},
+		// 未收录的顶级域取最后一段
+		{"abc.unknowntld", "unknowntld"},
+		// 内置列表默认不包含私有域后缀
+		{"abc.github.io", "io"},
+	}
+	for _, tt := range tests {
+		if got := list.PublicSuffix(tt.domain); got != tt.want {
+			t.Errorf("PublicSuffix(%q) = %q, want %q", tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	list, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		domain string
+		want   Parts
+	}{
+		{"abc.com", Parts{Label: "abc", Suffix: "com"}},
+		{"a.b.abc.com.cn", Parts{Subdomain: "a.b", Label: "abc", Suffix: "com.cn"}},
+		{"www.abc.ck", Parts{Label: "www", Suffix: "abc.ck"}},
+		{"www.ck", Parts{Label: "www", Suffix: "ck"}},
+		{"xn--fiq228c.xn--fiqs8s", Parts{Label: "xn--fiq228c", Suffix: "xn--fiqs8s"}},
+		{"www.xn--fiq228c.xn--fiqs8s", Parts{Subdomain: "www", Label: "xn--fiq228c", Suffix: "xn--fiqs8s"}},
+		{"ABC.Com.", Parts{Label: "ABC", Suffix: "Com"}},
+	}
+	for _, tt := range tests {
+		got, err := list.Split(tt.domain)
+		if err != nil {
+			t.Errorf("Split(%q) error = %v", tt.domain, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("Split(%q) = %+v, want %+v", tt.domain, *got, tt.want)
+		}
+	}
+
+	for _, domain := range []string{"com", "com.cn", "abc.ck", "abc..com", ""} {
+		if _, err := list.Split(domain); err == nil {
+			t.Errorf("Split(%q) error = nil, want error", domain)
+		}
+	}
+
+	if got, _ := list.Split("abc.com"); got.Domain() != "abc.com" {
+		t.Errorf("Domain() = %q, want abc.com", got.Domain())
+	}
+}
+
+func TestPrivateSuffixes(t *testing.T) {
+	tests := []struct {
+		includePrivate bool
+		domain         string
+		want           string
+	}{
+		{false, "abc.github.io", "io"},
+		{true, "abc.github.io", "github.io"},
+		{false, "abc.blogspot.com", "com"},
+		{true, "abc.blogspot.com", "blogspot.com"},
+		{true, "abc.com", "com"},
+	}
+	for _, tt := range tests {
+		list, err := Load("", tt.includePrivate)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if got := list.PublicSuffix(tt.domain); got != tt.want {
+			t.Errorf("includePrivate=%v: PublicSuffix(%q) = %q, want %q", tt.includePrivate, tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := `// 测试列表
+com
+*.ck
+!www.ck
+中国 注释
+// ===BEGIN PRIVATE DOMAINS===
+example.com
+`
+	list, err := Parse(strings.NewReader(data), false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := list.PublicSuffix("abc.xn--fiqs8s"); got != "xn--fiqs8s" {
+		t.Errorf("PublicSuffix(abc.xn--fiqs8s) = %q, want xn--fiqs8s", got)
+	}
+	if got := list.PublicSuffix("abc.example.com"); got != "com" {
+		t.Errorf("PublicSuffix(abc.example.com) = %q, want com", got)
+	}
+
+	list, err = Parse(strings.NewReader(data), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := list.PublicSuffix("abc.example.com"); got != "example.com" {
+		t.Errorf("PublicSuffix(abc.example.com) = %q, want example.com", got)
+	}
+
+	if _, err := Parse(strings.NewReader("// 只有注释\n"), false); err == nil {
+		t.Error("Parse() error = nil, want error for empty list")
+	}
+}
@@ -0,0 +1,86 @@
+// Package rdap 实现RDAP（RFC 7482/7483）域名注册数据查询客户端
+package rdap
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// 内置的RDAP引导文件，格式同 https://data.iana.org/rdap/dns.json，但只是其中常用顶级域的部分子集，
+// 其他顶级域没有RDAP服务地址；可以通过配置 dynamic.rdap.bootstrapFile 指定从IANA下载的完整文件
+//
+//go:embed dns_subset.json
+var embeddedBootstrap []byte
+
+// Bootstrap 表示顶级域到RDAP服务地址的映射（RFC 7484）
+type Bootstrap struct {
+	Publication string
+	services    map[string][]string // 顶级域 -> RDAP基础地址
+}
+
+// bootstrapFile 是引导文件的JSON结构
+type bootstrapFile struct {
+	Description string       `json:"description"`
+	Publication string       `json:"publication"`
+	Services    [][][]string `json:"services"`
+	Version     string       `json:"version"`
+}
+
+// DefaultBootstrap 返回内置的引导文件，只包含部分顶级域
+func DefaultBootstrap() (*Bootstrap, error) {
+	return ParseBootstrap(bytes.NewReader(embeddedBootstrap))
+}
+
+// LoadBootstrap 加载引导文件，path 为空时返回内置的引导文件
+func LoadBootstrap(path string) (*Bootstrap, error) {
+	if path == "" {
+		return DefaultBootstrap()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开RDAP引导文件失败: %w", err)
+	}
+	defer f.Close()
+
+	return ParseBootstrap(f)
+}
+
+// ParseBootstrap 解析IANA格式的RDAP引导文件
+func ParseBootstrap(r io.Reader) (*Bootstrap, error) {
+	var file bootstrapFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("解析RDAP引导文件失败: %w", err)
+	}
+
+	b := &Bootstrap{
+		Publication: file.Publication,
+		services:    make(map[string][]string),
+	}
+	for i, service := range file.Services {
+		if len(service) != 2 || len(service[1]) == 0 {
+			return nil, fmt.Errorf("RDAP引导文件第 %d 个服务格式无效", i+1)
+		}
+		for _, tld := range service[0] {
+			b.services[strings.ToLower(tld)] = service[1]
+		}
+	}
+
+	return b, nil
+}
+
+// URLsFor 返回域名对应的RDAP基础地址，优先匹配最长的后缀
+func (b *Bootstrap) URLsFor(domain string) []string {
+	labels := strings.Split(strings.ToLower(strings.Trim(domain, ".")), ".")
+	for i := 1; i < len(labels); i++ {
+		if urls, ok := b.services[strings.Join(labels[i:], ".")]; ok {
+			return urls
+		}
+	}
+	return nil
+}
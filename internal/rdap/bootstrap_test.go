@@ -0,0 +1,66 @@
+package rdap
+
+import (
+	"strings"
+	"testing"
+)
+
+const testBootstrap = `{
+  "version": "1.0",
+  "services": [
+    [["com", "net"], ["https://rdap.example.com/"]],
+    [["cn"], ["https://rdap.cn.example/", "http://rdap.cn.example/"]],
+    [["com.cn"], ["https://rdap.comcn.example/"]]
+  ]
+}`
+
+func TestBootstrapURLsFor(t *testing.T) {
+	b, err := ParseBootstrap(strings.NewReader(testBootstrap))
+	if err != nil {
+		t.Fatalf("ParseBootstrap() error = %v", err)
+	}
+
+	tests := []struct {
+		domain string
+		want   []string
+	}{
+		{"abc.com", []string{"https://rdap.example.com/"}},
+		{"ABC.NET.", []string{"https://rdap.example.com/"}},
+		{"abc.cn", []string{"https://rdap.cn.example/", "http://rdap.cn.example/"}},
+		{"abc.com.cn", []string{"https://rdap.comcn.example/"}},
+		{"abc.org", nil},
+		{"com", nil},
+	}
+	for _, tt := range tests {
+		got := b.URLsFor(tt.domain)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("URLsFor(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestParseBootstrapInvalid(t *testing.T) {
+	for _, data := range []string{
+		`not json`,
+		`{"services": [[["com"]]]}`,
+		`{"services": [[["com"], []]]}`,
+	} {
+		if _, err := ParseBootstrap(strings.NewReader(data)); err == nil {
+			t.Errorf("ParseBootstrap(%s) error = nil, want error", data)
+		}
+	}
+}
+
+func TestDefaultBootstrap(t *testing.T) {
+	b, err := DefaultBootstrap()
+	if err != nil {
+		t.Fatalf("DefaultBootstrap() error = %v", err)
+	}
+	if urls := b.URLsFor("abc.com"); len(urls) == 0 {
+		t.Error("内置引导文件缺少 com 的服务地址")
+	}
+	// 内置文件只是部分子集
+	if urls := b.URLsFor("abc.museum"); urls != nil {
+		t.Errorf("URLsFor(abc.museum) = %v, want nil", urls)
+	}
+}
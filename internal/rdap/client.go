@@ -0,0 +1,141 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound 表示RDAP服务器没有该域名的记录（通常意味着未注册）
+	ErrNotFound = errors.New("rdap: 域名未注册或无记录")
+	// ErrNoService 表示该顶级域没有可用的RDAP服务
+	ErrNoService = errors.New("rdap: 顶级域没有RDAP服务")
+)
+
+// Domain 表示解析后的RDAP域名对象
+type Domain struct {
+	Handle      string    // 注册局句柄
+	LDHName     string    // ASCII形式的域名
+	UnicodeName string    // Unicode形式的域名
+	Registrar   string    // 注册商名称
+	Registered  time.Time // 注册日期（registration事件）
+	Expires     time.Time // 到期日期（expiration事件）
+	LastChanged time.Time // 最后变更日期（last changed事件）
+	Status      []string  // 域名状态，如 client transfer prohibited
+	NameServers []string  // 域名服务器
+}
+
+// Client 是RDAP查询客户端
+type Client struct {
+	// HTTPClient 用于发送请求，为空时使用http.DefaultClient
+	HTTPClient *http.Client
+	// Bootstrap 提供顶级域到RDAP服务地址的映射
+	Bootstrap *Bootstrap
+	// Servers 覆盖引导文件中的服务地址，如 {"com": "http://127.0.0.1:8081/"}
+	Servers map[string]string
+	// Timeout 是单次查询的超时时间
+	Timeout time.Duration
+}
+
+// NewClient 创建一个新的Client实例
+func NewClient(bootstrap *Bootstrap) *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		Bootstrap:  bootstrap,
+		Servers:    make(map[string]string),
+		Timeout:    10 * time.Second,
+	}
+}
+
+// Lookup 查询域名的RDAP注册数据
+// domain 需为punycode形式的可注册域名，如 abc.com
+func (c *Client) Lookup(ctx context.Context, domain string) (*Domain, error) {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+
+	baseURLs := c.baseURLs(domain)
+	if len(baseURLs) == 0 {
+		return nil, ErrNoService
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	// 依次尝试各个服务地址，直到获得明确的结果
+	var lastErr error
+	for _, base := range baseURLs {
+		result, err := c.query(ctx, base, domain)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			return result, err
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// baseURLs 返回域名对应的RDAP基础地址
+func (c *Client) baseURLs(domain string) []string {
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		if server, ok := c.Servers[strings.Join(labels[i:], ".")]; ok {
+			return []string{server}
+		}
+	}
+	if c.Bootstrap == nil {
+		return nil
+	}
+	return c.Bootstrap.URLsFor(domain)
+}
+
+// query 向单个RDAP服务器查询域名
+func (c *Client) query(ctx context.Context, base, domain string) (*Domain, error) {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"domain/"+domain, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: 创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: 请求 %s 失败: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("rdap: %s 返回状态码 %d", req.URL, resp.StatusCode)
+	}
+
+	// 响应大小限制为2MB
+	var obj domainObject
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 2<<20)).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("rdap: 解析 %s 的响应失败: %w", req.URL, err)
+	}
+	if obj.ObjectClassName != "" && obj.ObjectClassName != "domain" {
+		return nil, fmt.Errorf("rdap: %s 返回了非域名对象 %s", req.URL, obj.ObjectClassName)
+	}
+
+	return obj.toDomain(), nil
+}
@@ -0,0 +1,133 @@
+package rdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testDomainObject = `{
+  "objectClassName": "domain",
+  "handle": "2138514_DOMAIN_COM-VRSN",
+  "ldhName": "EXAMPLE.COM",
+  "status": ["client delete prohibited", "client transfer prohibited"],
+  "events": [
+    {"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+    {"eventAction": "expiration", "eventDate": "2026-08-13T04:00:00Z"},
+    {"eventAction": "last changed", "eventDate": "2025-08-14T07:01:38Z"}
+  ],
+  "entities": [{
+    "roles": ["registrar"],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]
+  }],
+  "nameservers": [{"ldhName": "A.IANA-SERVERS.NET"}, {"ldhName": "B.IANA-SERVERS.NET"}]
+}`
+
+// newTestClient 创建将 com 指向测试服务器的客户端
+func newTestClient(handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := NewClient(nil)
+	client.HTTPClient = server.Client()
+	client.Servers["com"] = server.URL
+	return client, server
+}
+
+func TestLookup(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.Write([]byte(testDomainObject))
+	})
+	defer server.Close()
+
+	d, err := client.Lookup(context.Background(), "Example.COM.")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if d.LDHName != "example.com" || d.Registrar != "Example Registrar, Inc." {
+		t.Errorf("LDHName, Registrar = %q, %q", d.LDHName, d.Registrar)
+	}
+	if want := time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC); !d.Registered.Equal(want) {
+		t.Errorf("Registered = %v, want %v", d.Registered, want)
+	}
+	if want := time.Date(2026, 8, 13, 4, 0, 0, 0, time.UTC); !d.Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", d.Expires, want)
+	}
+	if len(d.NameServers) != 2 || d.NameServers[0] != "a.iana-servers.net" {
+		t.Errorf("NameServers = %v", d.NameServers)
+	}
+	if len(d.Status) != 2 {
+		t.Errorf("Status = %v", d.Status)
+	}
+}
+
+func TestLookupErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"404为未注册", http.StatusNotFound, "", ErrNotFound},
+		{"5xx", http.StatusServiceUnavailable, "", nil},
+		{"无效JSON", http.StatusOK, "{", nil},
+		{"非域名对象", http.StatusOK, `{"objectClassName": "entity"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			_, err := client.Lookup(context.Background(), "example.com")
+			if err == nil {
+				t.Fatal("Lookup() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && errors.Is(err, ErrNotFound) {
+				t.Errorf("Lookup() error = %v, want an error other than ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestLookupNoService(t *testing.T) {
+	b, err := DefaultBootstrap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(b)
+	if _, err := client.Lookup(context.Background(), "example.museum"); !errors.Is(err, ErrNoService) {
+		t.Errorf("Lookup() error = %v, want ErrNoService", err)
+	}
+}
+
+func TestLookupTriesNextServer(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testDomainObject))
+	}))
+	defer working.Close()
+
+	client := NewClient(&Bootstrap{services: map[string][]string{"com": {failing.URL, working.URL}}})
+	d, err := client.Lookup(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if d.LDHName != "example.com" {
+		t.Errorf("LDHName = %q", d.LDHName)
+	}
+}
@@ -0,0 +1,119 @@
+package rdap
+
+import (
+	"strings"
+	"time"
+)
+
+// domainObject 是RFC 7483定义的域名对象中本系统使用的部分
+type domainObject struct {
+	ObjectClassName string       `json:"objectClassName"`
+	Handle          string       `json:"handle"`
+	LDHName         string       `json:"ldhName"`
+	UnicodeName     string       `json:"unicodeName"`
+	Status          []string     `json:"status"`
+	Events          []event      `json:"events"`
+	Entities        []entity     `json:"entities"`
+	Nameservers     []nameserver `json:"nameservers"`
+}
+
+type event struct {
+	EventAction string `json:"eventAction"`
+	EventDate   string `json:"eventDate"`
+}
+
+type entity struct {
+	Roles      []string      `json:"roles"`
+	VCardArray []interface{} `json:"vcardArray"`
+	PublicIDs  []struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	} `json:"publicIds"`
+	Entities []entity `json:"entities"`
+}
+
+type nameserver struct {
+	LDHName string `json:"ldhName"`
+}
+
+// toDomain 转换为对外的Domain结构
+func (o *domainObject) toDomain() *Domain {
+	d := &Domain{
+		Handle:      o.Handle,
+		LDHName:     strings.ToLower(o.LDHName),
+		UnicodeName: o.UnicodeName,
+		Status:      o.Status,
+	}
+
+	for _, e := range o.Events {
+		date := parseEventDate(e.EventDate)
+		switch strings.ToLower(e.EventAction) {
+		case "registration":
+			d.Registered = date
+		case "expiration":
+			d.Expires = date
+		case "last changed":
+			d.LastChanged = date
+		}
+	}
+
+	for _, ns := range o.Nameservers {
+		if ns.LDHName != "" {
+			d.NameServers = append(d.NameServers, strings.ToLower(ns.LDHName))
+		}
+	}
+
+	if registrar := findRole(o.Entities, "registrar"); registrar != nil {
+		d.Registrar = registrar.fullName()
+	}
+
+	return d
+}
+
+// findRole 查找具有指定角色的实体（含嵌套实体）
+func findRole(entities []entity, role string) *entity {
+	for i := range entities {
+		for _, r := range entities[i].Roles {
+			if strings.EqualFold(r, role) {
+				return &entities[i]
+			}
+		}
+		if found := findRole(entities[i].Entities, role); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// fullName 从jCard（RFC 7095）中提取fn属性
+// vcardArray 形如 ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "名称"]]]
+func (e *entity) fullName() string {
+	if len(e.VCardArray) != 2 {
+		return ""
+	}
+	props, ok := e.VCardArray[1].([]interface{})
+	if !ok {
+		return ""
+	}
+	for _, p := range props {
+		prop, ok := p.([]interface{})
+		if !ok || len(prop) < 4 {
+			continue
+		}
+		if name, _ := prop[0].(string); name == "fn" {
+			value, _ := prop[3].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+// parseEventDate 解析事件日期，RDAP使用RFC 3339格式
+func parseEventDate(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
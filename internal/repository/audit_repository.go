@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"domainweb/internal/model"
+)
+
+// SQLAuditRepository 基于database/sql实现AuditRepository，支持MySQL和SQLite
+type SQLAuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository 创建一个新的SQLAuditRepository实例
+func NewAuditRepository(db *sql.DB) *SQLAuditRepository {
+	return &SQLAuditRepository{db: db}
+}
+
+// SaveAuditLog 保存一条审计日志并回填ID
+func (r *SQLAuditRepository) SaveAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	query := `INSERT INTO audit_logs (entity_type, entity_id, entity_name, action, actor, source, reason,
+			  before_value, after_value, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		entry.EntityType,
+		entry.EntityID,
+		entry.EntityName,
+		entry.Action,
+		entry.Actor,
+		entry.Source,
+		entry.Reason,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("保存审计日志失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取审计日志ID失败: %w", err)
+	}
+	entry.ID = id
+	return nil
+}
+
+// ListAuditLogs 按条件查询审计日志，按时间倒序排列
+func (r *SQLAuditRepository) ListAuditLogs(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.EntityName != "" {
+		conditions = append(conditions, "entity_name LIKE ?")
+		args = append(args, "%"+filter.EntityName+"%")
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+
+	query := `SELECT id, entity_type, entity_id, entity_name, action, actor, source, reason,
+			  before_value, after_value, created_at
+			  FROM audit_logs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询审计日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.AuditLog
+	for rows.Next() {
+		var entry model.AuditLog
+		var before, after sql.NullString
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.EntityName,
+			&entry.Action,
+			&entry.Actor,
+			&entry.Source,
+			&entry.Reason,
+			&before,
+			&after,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描审计日志行失败: %w", err)
+		}
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代审计日志行失败: %w", err)
+	}
+	return entries, nil
+}
+
+// nullJSON 将空的JSON值保存为NULL
+func nullJSON(value json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(value), Valid: len(value) > 0}
+}
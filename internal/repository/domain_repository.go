@@ -1,29 +1,36 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"domainweb/internal/model"
 )
 
-// DomainRepository 处理域名相关的数据库操作
-type DomainRepository struct {
-	db *sql.DB
+// SQLDomainRepository 基于database/sql实现DomainRepository，支持MySQL和SQLite
+type SQLDomainRepository struct {
+	db      *sql.DB
+	dialect Dialect
 }
 
-// NewDomainRepository 创建一个新的DomainRepository实例
-func NewDomainRepository(db *sql.DB) *DomainRepository {
-	return &DomainRepository{db: db}
+// NewDomainRepository 创建一个新的SQLDomainRepository实例
+func NewDomainRepository(db *sql.DB, dialect Dialect) *SQLDomainRepository {
+	return &SQLDomainRepository{db: db, dialect: dialect}
 }
 
-// GetDomainAttributes 获取所有域名属性规则
-func (r *DomainRepository) GetDomainAttributes() ([]model.DomainAttribute, error) {
-	query := `SELECT id, attribute_name, attribute_type, price_factor, grade_factor, attribute_value
-			  FROM domain_attributes`
+// domainAttributeColumns 是读取域名属性时查询的列，与 scanDomainAttribute 的顺序一致
+const domainAttributeColumns = `id, attribute_name, attribute_type, price_factor, grade_factor, attribute_value, min_value, max_value`
 
-	rows, err := r.db.Query(query)
+// GetDomainAttributes 获取所有域名属性规则，按ID排序
+func (r *SQLDomainRepository) GetDomainAttributes(ctx context.Context) ([]model.DomainAttribute, error) {
+	query := `SELECT ` + domainAttributeColumns + `
+			  FROM domain_attributes
+			  ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("查询域名属性失败: %w", err)
 	}
@@ -31,18 +38,11 @@ func (r *DomainRepository) GetDomainAttributes() ([]model.DomainAttribute, error
 
 	var attributes []model.DomainAttribute
 	for rows.Next() {
-		var attr model.DomainAttribute
-		if err := rows.Scan(
-			&attr.ID,
-			&attr.AttributeName,
-			&attr.AttributeType,
-			&attr.PriceFactor,
-			&attr.GradeFactor,
-			&attr.AttributeValue,
-		); err != nil {
+		attr, err := scanDomainAttribute(rows)
+		if err != nil {
 			return nil, fmt.Errorf("扫描域名属性行失败: %w", err)
 		}
-		attributes = append(attributes, attr)
+		attributes = append(attributes, *attr)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -52,13 +52,178 @@ func (r *DomainRepository) GetDomainAttributes() ([]model.DomainAttribute, error
 	return attributes, nil
 }
 
+// GetAttribute 获取单个域名属性，不存在时返回 ErrNotFound
+func (r *SQLDomainRepository) GetAttribute(ctx context.Context, id int64) (*model.DomainAttribute, error) {
+	return getAttribute(ctx, r.db, id)
+}
+
+// CreateAttribute 保存一个新的域名属性并回填ID
+func (r *SQLDomainRepository) CreateAttribute(ctx context.Context, attr *model.DomainAttribute) error {
+	return createAttribute(ctx, r.db, attr)
+}
+
+// UpdateAttribute 更新域名属性，不存在时返回 ErrNotFound
+func (r *SQLDomainRepository) UpdateAttribute(ctx context.Context, attr *model.DomainAttribute) error {
+	return updateAttribute(ctx, r.db, attr)
+}
+
+// DeleteAttribute 删除域名属性，不存在时返回 ErrNotFound
+func (r *SQLDomainRepository) DeleteAttribute(ctx context.Context, id int64) error {
+	return deleteAttribute(ctx, r.db, id)
+}
+
+// ApplyRuleChanges 在一个事务中应用域名属性和估价规则的变更，并回填新增记录的ID
+// 任一变更失败时回滚全部变更
+func (r *SQLDomainRepository) ApplyRuleChanges(ctx context.Context, changes *model.RuleChanges) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 先删除再新增，避免新记录与待删除的记录冲突
+	for _, id := range changes.DeleteAttributes {
+		if err := deleteAttribute(ctx, tx, id); err != nil {
+			return fmt.Errorf("删除域名属性 %d 失败: %w", id, err)
+		}
+	}
+	for i := range changes.UpdateAttributes {
+		if err := updateAttribute(ctx, tx, &changes.UpdateAttributes[i]); err != nil {
+			return fmt.Errorf("更新域名属性 %d 失败: %w", changes.UpdateAttributes[i].ID, err)
+		}
+	}
+	for i := range changes.CreateAttributes {
+		if err := createAttribute(ctx, tx, &changes.CreateAttributes[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range changes.DeleteRules {
+		if err := deleteValuationRule(ctx, tx, id); err != nil {
+			return fmt.Errorf("删除估价规则 %d 失败: %w", id, err)
+		}
+	}
+	for i := range changes.UpdateRules {
+		if err := updateValuationRule(ctx, tx, &changes.UpdateRules[i]); err != nil {
+			return fmt.Errorf("更新估价规则 %d 失败: %w", changes.UpdateRules[i].ID, err)
+		}
+	}
+	for i := range changes.CreateRules {
+		if err := createValuationRule(ctx, tx, &changes.CreateRules[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交规则变更失败: %w", err)
+	}
+	return nil
+}
+
+// dbExecutor 是 *sql.DB 和 *sql.Tx 共有的方法，使单条修改和事务中的批量修改共用同一套语句
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getAttribute 获取单个域名属性，不存在时返回 ErrNotFound
+func getAttribute(ctx context.Context, db dbExecutor, id int64) (*model.DomainAttribute, error) {
+	query := `SELECT ` + domainAttributeColumns + `
+			  FROM domain_attributes
+			  WHERE id = ?`
+
+	attr, err := scanDomainAttribute(db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询域名属性失败: %w", err)
+	}
+	return attr, nil
+}
+
+// scanDomainAttribute 读取一行域名属性
+func scanDomainAttribute(row rowScanner) (*model.DomainAttribute, error) {
+	var attr model.DomainAttribute
+	var minValue, maxValue sql.NullFloat64
+	if err := row.Scan(
+		&attr.ID,
+		&attr.AttributeName,
+		&attr.AttributeType,
+		&attr.PriceFactor,
+		&attr.GradeFactor,
+		&attr.AttributeValue,
+		&minValue,
+		&maxValue,
+	); err != nil {
+		return nil, err
+	}
+	if minValue.Valid {
+		attr.MinValue = &minValue.Float64
+	}
+	if maxValue.Valid {
+		attr.MaxValue = &maxValue.Float64
+	}
+	return &attr, nil
+}
+
+// createAttribute 保存一个新的域名属性并回填ID
+func createAttribute(ctx context.Context, db dbExecutor, attr *model.DomainAttribute) error {
+	query := `INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, grade_factor, attribute_value, min_value, max_value, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now()
+	result, err := db.ExecContext(ctx, query, attr.AttributeName, attr.AttributeType, attr.PriceFactor, attr.GradeFactor, attr.AttributeValue,
+		attr.MinValue, attr.MaxValue, now, now)
+	if err != nil {
+		return fmt.Errorf("保存域名属性失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取域名属性ID失败: %w", err)
+	}
+	attr.ID = id
+	return nil
+}
+
+// updateAttribute 更新域名属性，不存在时返回 ErrNotFound
+func updateAttribute(ctx context.Context, db dbExecutor, attr *model.DomainAttribute) error {
+	query := `UPDATE domain_attributes
+			  SET attribute_name = ?, attribute_type = ?, price_factor = ?, grade_factor = ?, attribute_value = ?,
+			      min_value = ?, max_value = ?, updated_at = ?
+			  WHERE id = ?`
+
+	result, err := db.ExecContext(ctx, query, attr.AttributeName, attr.AttributeType, attr.PriceFactor, attr.GradeFactor, attr.AttributeValue,
+		attr.MinValue, attr.MaxValue, time.Now(), attr.ID)
+	if err != nil {
+		return fmt.Errorf("更新域名属性失败: %w", err)
+	}
+
+	// MySQL默认返回实际变化的行数，内容未变时为0，需要再确认记录是否存在
+	err = checkAffected(result, "更新域名属性失败")
+	if errors.Is(err, ErrNotFound) {
+		_, err = getAttribute(ctx, db, attr.ID)
+	}
+	return err
+}
+
+// deleteAttribute 删除域名属性，不存在时返回 ErrNotFound
+func deleteAttribute(ctx context.Context, db dbExecutor, id int64) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM domain_attributes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("删除域名属性失败: %w", err)
+	}
+	return checkAffected(result, "删除域名属性失败")
+}
+
 // GetAttributesByType 根据属性类型获取域名属性
-func (r *DomainRepository) GetAttributesByType(attrType string) ([]model.DomainAttribute, error) {
-	query := `SELECT id, attribute_name, attribute_type, price_factor, grade_factor, attribute_value
+func (r *SQLDomainRepository) GetAttributesByType(ctx context.Context, attrType string) ([]model.DomainAttribute, error) {
+	query := `SELECT ` + domainAttributeColumns + `
 			  FROM domain_attributes
 			  WHERE attribute_type = ?`
 
-	rows, err := r.db.Query(query, attrType)
+	rows, err := r.db.QueryContext(ctx, query, attrType)
 	if err != nil {
 		return nil, fmt.Errorf("查询属性类型失败: %w", err)
 	}
@@ -66,18 +231,11 @@ func (r *DomainRepository) GetAttributesByType(attrType string) ([]model.DomainA
 
 	var attributes []model.DomainAttribute
 	for rows.Next() {
-		var attr model.DomainAttribute
-		if err := rows.Scan(
-			&attr.ID,
-			&attr.AttributeName,
-			&attr.AttributeType,
-			&attr.PriceFactor,
-			&attr.GradeFactor,
-			&attr.AttributeValue,
-		); err != nil {
+		attr, err := scanDomainAttribute(rows)
+		if err != nil {
 			return nil, fmt.Errorf("扫描属性类型行失败: %w", err)
 		}
-		attributes = append(attributes, attr)
+		attributes = append(attributes, *attr)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -88,12 +246,12 @@ func (r *DomainRepository) GetAttributesByType(attrType string) ([]model.DomainA
 }
 
 // GetTLDAttributes 获取所有TLD属性
-func (r *DomainRepository) GetTLDAttributes() (map[string]model.DomainAttribute, error) {
-	query := `SELECT id, attribute_name, attribute_type, price_factor, grade_factor, attribute_value
+func (r *SQLDomainRepository) GetTLDAttributes(ctx context.Context) (map[string]model.DomainAttribute, error) {
+	query := `SELECT ` + domainAttributeColumns + `
 			  FROM domain_attributes
 			  WHERE attribute_name LIKE '%后缀'`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("查询TLD属性失败: %w", err)
 	}
@@ -101,18 +259,11 @@ func (r *DomainRepository) GetTLDAttributes() (map[string]model.DomainAttribute,
 
 	tldAttrs := make(map[string]model.DomainAttribute)
 	for rows.Next() {
-		var attr model.DomainAttribute
-		if err := rows.Scan(
-			&attr.ID,
-			&attr.AttributeName,
-			&attr.AttributeType,
-			&attr.PriceFactor,
-			&attr.GradeFactor,
-			&attr.AttributeValue,
-		); err != nil {
+		attr, err := scanDomainAttribute(rows)
+		if err != nil {
 			return nil, fmt.Errorf("扫描TLD属性行失败: %w", err)
 		}
-		tldAttrs[attr.AttributeValue] = attr
+		tldAttrs[attr.AttributeValue] = *attr
 	}
 
 	if err := rows.Err(); err != nil {
@@ -122,8 +273,199 @@ func (r *DomainRepository) GetTLDAttributes() (map[string]model.DomainAttribute,
 	return tldAttrs, nil
 }
 
+// valuationRuleColumns 是读取估价规则时查询的列，与 scanValuationRule 的顺序一致
+const valuationRuleColumns = `id, attribute_key, operator, compare_value, min_value, max_value, expression,
+			  price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at`
+
+// GetValuationRules 获取所有动态属性估价规则，按优先级排序
+func (r *SQLDomainRepository) GetValuationRules(ctx context.Context) ([]model.ValuationRule, error) {
+	query := `SELECT ` + valuationRuleColumns + `
+			  FROM valuation_rules
+			  ORDER BY priority, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询估价规则失败: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.ValuationRule
+	for rows.Next() {
+		rule, err := scanValuationRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描估价规则行失败: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代估价规则行失败: %w", err)
+	}
+
+	return rules, nil
+}
+
+// GetValuationRule 获取单条估价规则，不存在时返回 ErrNotFound
+func (r *SQLDomainRepository) GetValuationRule(ctx context.Context, id int64) (*model.ValuationRule, error) {
+	return getValuationRule(ctx, r.db, id)
+}
+
+// getValuationRule 获取单条估价规则，不存在时返回 ErrNotFound
+func getValuationRule(ctx context.Context, db dbExecutor, id int64) (*model.ValuationRule, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+valuationRuleColumns+` FROM valuation_rules WHERE id = ?`, id)
+	rule, err := scanValuationRule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询估价规则失败: %w", err)
+	}
+	return rule, nil
+}
+
+// scanValuationRule 读取一行估价规则
+func scanValuationRule(row rowScanner) (*model.ValuationRule, error) {
+	var rule model.ValuationRule
+	var minValue, maxValue sql.NullFloat64
+	if err := row.Scan(
+		&rule.ID,
+		&rule.AttributeKey,
+		&rule.Operator,
+		&rule.Value,
+		&minValue,
+		&maxValue,
+		&rule.Expression,
+		&rule.PriceFactor,
+		&rule.GradeFactor,
+		&rule.Label,
+		&rule.Description,
+		&rule.Priority,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if minValue.Valid {
+		rule.MinValue = &minValue.Float64
+	}
+	if maxValue.Valid {
+		rule.MaxValue = &maxValue.Float64
+	}
+	return &rule, nil
+}
+
+// CreateValuationRule 保存一条新的估价规则并回填ID
+func (r *SQLDomainRepository) CreateValuationRule(ctx context.Context, rule *model.ValuationRule) error {
+	return createValuationRule(ctx, r.db, rule)
+}
+
+// DeleteValuationRule 删除估价规则，不存在时返回 ErrNotFound
+func (r *SQLDomainRepository) DeleteValuationRule(ctx context.Context, id int64) error {
+	return deleteValuationRule(ctx, r.db, id)
+}
+
+// createValuationRule 保存一条新的估价规则并回填ID
+func createValuationRule(ctx context.Context, db dbExecutor, rule *model.ValuationRule) error {
+	query := `INSERT INTO valuation_rules (attribute_key, operator, compare_value, min_value, max_value, expression,
+			  price_factor, grade_factor, label, description, priority, enabled, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now()
+	result, err := db.ExecContext(ctx,
+		query,
+		rule.AttributeKey,
+		rule.Operator,
+		rule.Value,
+		rule.MinValue,
+		rule.MaxValue,
+		rule.Expression,
+		rule.PriceFactor,
+		rule.GradeFactor,
+		rule.Label,
+		rule.Description,
+		rule.Priority,
+		rule.Enabled,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("保存估价规则失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取估价规则ID失败: %w", err)
+	}
+	rule.ID = id
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	return nil
+}
+
+// updateValuationRule 更新估价规则，不存在时返回 ErrNotFound
+func updateValuationRule(ctx context.Context, db dbExecutor, rule *model.ValuationRule) error {
+	query := `UPDATE valuation_rules
+			  SET attribute_key = ?, operator = ?, compare_value = ?, min_value = ?, max_value = ?, expression = ?,
+			  price_factor = ?, grade_factor = ?, label = ?, description = ?, priority = ?, enabled = ?, updated_at = ?
+			  WHERE id = ?`
+
+	now := time.Now()
+	result, err := db.ExecContext(ctx,
+		query,
+		rule.AttributeKey,
+		rule.Operator,
+		rule.Value,
+		rule.MinValue,
+		rule.MaxValue,
+		rule.Expression,
+		rule.PriceFactor,
+		rule.GradeFactor,
+		rule.Label,
+		rule.Description,
+		rule.Priority,
+		rule.Enabled,
+		now,
+		rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("更新估价规则失败: %w", err)
+	}
+
+	// 与 updateAttribute 相同，MySQL在内容未变时返回0行
+	err = checkAffected(result, "更新估价规则失败")
+	if errors.Is(err, ErrNotFound) {
+		_, err = getValuationRule(ctx, db, rule.ID)
+	}
+	if err == nil {
+		rule.UpdatedAt = now
+	}
+	return err
+}
+
+// deleteValuationRule 删除估价规则，不存在时返回 ErrNotFound
+func deleteValuationRule(ctx context.Context, db dbExecutor, id int64) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM valuation_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("删除估价规则失败: %w", err)
+	}
+	return checkAffected(result, "删除估价规则失败")
+}
+
+// checkAffected 在没有记录受影响时返回 ErrNotFound
+func checkAffected(result sql.Result, message string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // SaveDomainInfo 保存域名基本信息
-func (r *DomainRepository) SaveDomainInfo(domain *model.Domain) error {
+func (r *SQLDomainRepository) SaveDomainInfo(ctx context.Context, domain *model.Domain) error {
 	query := `INSERT INTO domains (name, tld, length, structure, register_date, expire_date, created_at, updated_at)
 			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 			  ON DUPLICATE KEY UPDATE
@@ -131,9 +473,18 @@ func (r *DomainRepository) SaveDomainInfo(domain *model.Domain) error {
 			  register_date = VALUES(register_date),
 			  expire_date = VALUES(expire_date),
 			  updated_at = VALUES(updated_at)`
+	if r.dialect == SQLite {
+		query = `INSERT INTO domains (name, tld, length, structure, register_date, expire_date, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET
+			  structure = excluded.structure,
+			  register_date = excluded.register_date,
+			  expire_date = excluded.expire_date,
+			  updated_at = excluded.updated_at`
+	}
 
 	now := time.Now()
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		query,
 		domain.Name,
 		domain.TLD,
@@ -0,0 +1,94 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+func TestApplyRuleChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewDomainRepository(testutil.OpenMigratedSQLite(t), repository.SQLite)
+
+	attrs, err := repo.GetDomainAttributes(ctx)
+	if err != nil || len(attrs) < 2 {
+		t.Fatalf("GetDomainAttributes() = %d, %v", len(attrs), err)
+	}
+	rules, err := repo.GetValuationRules(ctx)
+	if err != nil || len(rules) < 2 {
+		t.Fatalf("GetValuationRules() = %d, %v", len(rules), err)
+	}
+
+	updatedAttr := attrs[0]
+	updatedAttr.PriceFactor = 7.5
+	updatedRule := rules[0]
+	updatedRule.Label = "已修改"
+	changes := &model.RuleChanges{
+		CreateAttributes: []model.DomainAttribute{
+			{AttributeName: "dev后缀", AttributeType: "基础属性", PriceFactor: 1.2, AttributeValue: "dev"},
+		},
+		UpdateAttributes: []model.DomainAttribute{updatedAttr},
+		DeleteAttributes: []int64{attrs[1].ID},
+		CreateRules: []model.ValuationRule{
+			{AttributeKey: "registered", Operator: "exists", PriceFactor: 1, Label: "已注册", Priority: 500, Enabled: true},
+		},
+		UpdateRules: []model.ValuationRule{updatedRule},
+		DeleteRules: []int64{rules[1].ID},
+	}
+	if err := repo.ApplyRuleChanges(ctx, changes); err != nil {
+		t.Fatalf("ApplyRuleChanges() error = %v", err)
+	}
+
+	// 新增的记录回填ID
+	created := changes.CreateAttributes[0]
+	if created.ID == 0 || changes.CreateRules[0].ID == 0 {
+		t.Fatal("新增记录没有回填ID")
+	}
+	if got, err := repo.GetAttribute(ctx, created.ID); err != nil || got.AttributeValue != "dev" {
+		t.Errorf("GetAttribute(created) = %+v, %v", got, err)
+	}
+	if got, err := repo.GetAttribute(ctx, updatedAttr.ID); err != nil || got.PriceFactor != 7.5 {
+		t.Errorf("GetAttribute(updated) = %+v, %v", got, err)
+	}
+	if _, err := repo.GetAttribute(ctx, attrs[1].ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetAttribute(deleted) error = %v, want ErrNotFound", err)
+	}
+	if got, err := repo.GetValuationRule(ctx, updatedRule.ID); err != nil || got.Label != "已修改" {
+		t.Errorf("GetValuationRule(updated) = %+v, %v", got, err)
+	}
+	if _, err := repo.GetValuationRule(ctx, rules[1].ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetValuationRule(deleted) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestApplyRuleChangesRollback(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewDomainRepository(testutil.OpenMigratedSQLite(t), repository.SQLite)
+
+	before, err := repo.GetDomainAttributes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 更新不存在的记录失败时，同一事务中的删除和新增都被回滚
+	changes := &model.RuleChanges{
+		DeleteAttributes: []int64{before[0].ID},
+		UpdateAttributes: []model.DomainAttribute{{ID: 999999, AttributeName: "不存在", AttributeType: "其他属性"}},
+		CreateAttributes: []model.DomainAttribute{{AttributeName: "dev后缀", AttributeType: "基础属性", AttributeValue: "dev"}},
+	}
+	if err := repo.ApplyRuleChanges(ctx, changes); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ApplyRuleChanges() error = %v, want ErrNotFound", err)
+	}
+
+	after, err := repo.GetDomainAttributes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) || after[0].ID != before[0].ID {
+		t.Errorf("回滚后有 %d 个域名属性，应为 %d 个", len(after), len(before))
+	}
+}
@@ -1,34 +1,50 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"domainweb/internal/model"
 )
 
-// HistoryRepository 处理查询历史相关的数据库操作
-type HistoryRepository struct {
+// SQLHistoryRepository 基于database/sql实现HistoryRepository，支持MySQL和SQLite
+type SQLHistoryRepository struct {
 	db *sql.DB
 }
 
-// NewHistoryRepository 创建一个新的HistoryRepository实例
-func NewHistoryRepository(db *sql.DB) *HistoryRepository {
-	return &HistoryRepository{db: db}
+// NewHistoryRepository 创建一个新的SQLHistoryRepository实例
+func NewHistoryRepository(db *sql.DB) *SQLHistoryRepository {
+	return &SQLHistoryRepository{db: db}
 }
 
 // SaveHistory 保存查询历史记录
-func (r *HistoryRepository) SaveHistory(record *model.HistoryRecord) error {
-	query := `INSERT INTO history_records (domain, grade, price, estimation_date)
-			  VALUES (?, ?, ?, ?)`
+func (r *SQLHistoryRepository) SaveHistory(ctx context.Context, record *model.HistoryRecord) error {
+	inputs, err := marshalInputs(record.Inputs)
+	if err != nil {
+		return err
+	}
+
+	estimationDate := record.EstimationDate
+	if estimationDate.IsZero() {
+		estimationDate = time.Now()
+	}
+
+	query := `INSERT INTO history_records (domain, grade, price, rule_set_version, inputs, estimation_date)
+			  VALUES (?, ?, ?, ?, ?, ?)`
 
-	_, err := r.db.Exec(
+	_, err = r.db.ExecContext(ctx,
 		query,
 		record.Domain,
 		record.Grade,
 		record.Price,
-		time.Now(),
+		nullVersion(record.RuleSetVersion),
+		inputs,
+		estimationDate,
 	)
 
 	if err != nil {
@@ -38,27 +54,77 @@ func (r *HistoryRepository) SaveHistory(record *model.HistoryRecord) error {
 	return nil
 }
 
+// 每条批量插入语句包含的最大记录数，避免超出数据库的占位符数量限制
+const historyBatchSize = 500
+
+// SaveHistoryBatch 在一个事务中批量保存查询历史记录
+func (r *SQLHistoryRepository) SaveHistoryBatch(ctx context.Context, records []model.HistoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for start := 0; start < len(records); start += historyBatchSize {
+		end := start + historyBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*6)
+		for _, record := range records[start:end] {
+			estimationDate := record.EstimationDate
+			if estimationDate.IsZero() {
+				estimationDate = now
+			}
+			inputs, err := marshalInputs(record.Inputs)
+			if err != nil {
+				return err
+			}
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+			args = append(args, record.Domain, record.Grade, record.Price, nullVersion(record.RuleSetVersion), inputs, estimationDate)
+		}
+
+		query := `INSERT INTO history_records (domain, grade, price, rule_set_version, inputs, estimation_date) VALUES ` +
+			strings.Join(placeholders, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("批量保存历史记录失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交历史记录失败: %w", err)
+	}
+	return nil
+}
+
 // GetHistory 获取查询历史记录，可选择按域名筛选
-func (r *HistoryRepository) GetHistory(domain string, limit int) ([]model.HistoryRecord, error) {
+func (r *SQLHistoryRepository) GetHistory(ctx context.Context, domain string, limit int) ([]model.HistoryRecord, error) {
 	var query string
 	var args []interface{}
 
 	if domain != "" {
-		query = `SELECT id, domain, grade, price, estimation_date
+		query = `SELECT id, domain, grade, price, rule_set_version, inputs, estimation_date
 				FROM history_records
 				WHERE domain LIKE ?
 				ORDER BY estimation_date DESC
 				LIMIT ?`
 		args = append(args, "%"+domain+"%", limit)
 	} else {
-		query = `SELECT id, domain, grade, price, estimation_date
+		query = `SELECT id, domain, grade, price, rule_set_version, inputs, estimation_date
 				FROM history_records
 				ORDER BY estimation_date DESC
 				LIMIT ?`
 		args = append(args, limit)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("查询历史记录失败: %w", err)
 	}
@@ -66,17 +132,11 @@ func (r *HistoryRepository) GetHistory(domain string, limit int) ([]model.Histor
 
 	var records []model.HistoryRecord
 	for rows.Next() {
-		var record model.HistoryRecord
-		if err := rows.Scan(
-			&record.ID,
-			&record.Domain,
-			&record.Grade,
-			&record.Price,
-			&record.EstimationDate,
-		); err != nil {
+		record, err := scanHistoryRecord(rows)
+		if err != nil {
 			return nil, fmt.Errorf("扫描历史记录行失败: %w", err)
 		}
-		records = append(records, record)
+		records = append(records, *record)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -85,3 +145,62 @@ func (r *HistoryRepository) GetHistory(domain string, limit int) ([]model.Histor
 
 	return records, nil
 }
+
+// GetHistoryRecord 获取单条查询历史记录，不存在时返回 ErrNotFound
+func (r *SQLHistoryRepository) GetHistoryRecord(ctx context.Context, id int64) (*model.HistoryRecord, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, domain, grade, price, rule_set_version, inputs, estimation_date
+			  FROM history_records
+			  WHERE id = ?`, id)
+
+	record, err := scanHistoryRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询历史记录失败: %w", err)
+	}
+	return record, nil
+}
+
+// scanHistoryRecord 读取一行历史记录，规则集版本为空时记为0
+func scanHistoryRecord(row rowScanner) (*model.HistoryRecord, error) {
+	var record model.HistoryRecord
+	var version sql.NullInt64
+	var inputs sql.NullString
+	if err := row.Scan(
+		&record.ID,
+		&record.Domain,
+		&record.Grade,
+		&record.Price,
+		&version,
+		&inputs,
+		&record.EstimationDate,
+	); err != nil {
+		return nil, err
+	}
+	record.RuleSetVersion = version.Int64
+	if inputs.Valid {
+		record.Inputs = &model.EstimationInputs{}
+		if err := json.Unmarshal([]byte(inputs.String), record.Inputs); err != nil {
+			return nil, fmt.Errorf("解析历史记录 %d 的估价输入失败: %w", record.ID, err)
+		}
+	}
+	return &record, nil
+}
+
+// marshalInputs 将估价输入序列化为JSON，没有输入时保存为NULL
+func marshalInputs(inputs *model.EstimationInputs) (sql.NullString, error) {
+	if inputs == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("序列化估价输入失败: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// nullVersion 将0（未知版本）保存为NULL
+func nullVersion(version int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: version, Valid: version != 0}
+}
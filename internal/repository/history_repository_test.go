@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+func TestHistoryInputs(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewHistoryRepository(testutil.OpenMigratedSQLite(t))
+
+	reference := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []model.HistoryRecord{
+		{Domain: "abc.com", Price: 100, RuleSetVersion: 1, Inputs: &model.EstimationInputs{
+			DynamicAttributes: map[string]interface{}{"alexa_rank": 1200, "registered": true},
+			ReferenceDate:     reference,
+		}},
+		{Domain: "xyz.com", Price: 50},
+	}
+	if err := repo.SaveHistoryBatch(ctx, records); err != nil {
+		t.Fatalf("SaveHistoryBatch() error = %v", err)
+	}
+
+	got, err := repo.GetHistory(ctx, "abc.com", 10)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("GetHistory() = %v, %v", got, err)
+	}
+	inputs := got[0].Inputs
+	if inputs == nil || !inputs.ReferenceDate.Equal(reference) {
+		t.Fatalf("Inputs = %+v", inputs)
+	}
+	// JSON中的数字读回为float64
+	if inputs.DynamicAttributes["alexa_rank"] != 1200.0 || inputs.DynamicAttributes["registered"] != true {
+		t.Errorf("DynamicAttributes = %v", inputs.DynamicAttributes)
+	}
+
+	got, err = repo.GetHistory(ctx, "xyz.com", 10)
+	if err != nil || len(got) != 1 || got[0].Inputs != nil || got[0].RuleSetVersion != 0 {
+		t.Errorf("GetHistory(xyz.com) = %+v, %v, want no inputs and version 0", got, err)
+	}
+}
+
+func TestSaveHistoryEstimationDate(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewHistoryRepository(testutil.OpenMigratedSQLite(t))
+
+	// 单条和批量保存都使用估价结果中的估价日期
+	estimated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.SaveHistory(ctx, &model.HistoryRecord{Domain: "abc.com", EstimationDate: estimated}); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+	if err := repo.SaveHistoryBatch(ctx, []model.HistoryRecord{{Domain: "abc.com", EstimationDate: estimated}}); err != nil {
+		t.Fatalf("SaveHistoryBatch() error = %v", err)
+	}
+	// 没有估价日期时使用保存时间
+	if err := repo.SaveHistory(ctx, &model.HistoryRecord{Domain: "xyz.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := repo.GetHistory(ctx, "abc.com", 10)
+	if err != nil || len(records) != 2 {
+		t.Fatalf("GetHistory() = %v, %v", records, err)
+	}
+	for _, record := range records {
+		if !record.EstimationDate.Equal(estimated) {
+			t.Errorf("EstimationDate = %v, want %v", record.EstimationDate, estimated)
+		}
+	}
+	records, err = repo.GetHistory(ctx, "xyz.com", 10)
+	if err != nil || len(records) != 1 || time.Since(records[0].EstimationDate) > time.Minute {
+		t.Errorf("GetHistory(xyz.com) = %v, %v", records, err)
+	}
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"domainweb/internal/model"
+)
+
+// 每条批量插入语句包含的最大任务域名数，避免超出数据库的占位符数量限制
+const jobItemBatchSize = 500
+
+// SQLJobRepository 基于database/sql实现JobRepository，支持MySQL和SQLite
+type SQLJobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository 创建一个新的SQLJobRepository实例
+func NewJobRepository(db *sql.DB) *SQLJobRepository {
+	return &SQLJobRepository{db: db}
+}
+
+// CreateJob 在一个事务中创建任务及其包含的域名
+func (r *SQLJobRepository) CreateJob(ctx context.Context, job *model.Job, domains []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO estimation_jobs (id, status, total, done, failed, error, created_at, updated_at)
+		 VALUES (?, ?, ?, 0, 0, '', ?, ?)`,
+		job.ID, job.Status, job.Total, job.CreatedAt, job.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("创建任务失败: %w", err)
+	}
+
+	for start := 0; start < len(domains); start += jobItemBatchSize {
+		end := start + jobItemBatchSize
+		if end > len(domains) {
+			end = len(domains)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*5)
+		for i := start; i < end; i++ {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+			args = append(args, job.ID, i, domains[i], model.JobItemPending, job.CreatedAt)
+		}
+
+		query := `INSERT INTO estimation_job_items (job_id, position, domain, status, updated_at) VALUES ` +
+			strings.Join(placeholders, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("保存任务域名失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交任务失败: %w", err)
+	}
+	return nil
+}
+
+// GetJob 获取任务
+func (r *SQLJobRepository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, status, total, done, failed, error, created_at, updated_at, finished_at
+		 FROM estimation_jobs WHERE id = ?`, id)
+
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// ListUnfinishedJobs 获取所有等待执行或执行中的任务
+func (r *SQLJobRepository) ListUnfinishedJobs(ctx context.Context) ([]model.Job, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, status, total, done, failed, error, created_at, updated_at, finished_at
+		 FROM estimation_jobs WHERE status IN (?, ?) ORDER BY created_at`,
+		model.JobStatusPending, model.JobStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("查询未完成任务失败: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []model.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描任务行失败: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代任务行失败: %w", err)
+	}
+	return jobs, nil
+}
+
+// UpdateJobStatus 更新未结束任务的状态，已结束的任务保持不变
+func (r *SQLJobRepository) UpdateJobStatus(ctx context.Context, id, status, errMsg string) error {
+	now := time.Now()
+	var finishedAt interface{}
+	switch status {
+	case model.JobStatusCompleted, model.JobStatusCancelled, model.JobStatusFailed:
+		finishedAt = now
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE estimation_jobs SET status = ?, error = ?, updated_at = ?, finished_at = ?
+		 WHERE id = ? AND status IN (?, ?)`,
+		status, errMsg, now, finishedAt, id, model.JobStatusPending, model.JobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("更新任务状态失败: %w", err)
+	}
+	return nil
+}
+
+// GetPendingJobItems 按位置顺序获取任务中尚未估价的域名
+func (r *SQLJobRepository) GetPendingJobItems(ctx context.Context, jobID string, limit int) ([]model.JobItem, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT job_id, position, domain, status, result, error
+		 FROM estimation_job_items WHERE job_id = ? AND status = ?
+		 ORDER BY position LIMIT ?`,
+		jobID, model.JobItemPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询待估价域名失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobItems(rows)
+}
+
+// SaveJobItemResults 在一个事务中保存域名估价结果并累加任务进度
+func (r *SQLJobRepository) SaveJobItemResults(ctx context.Context, jobID string, items []model.JobItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`UPDATE estimation_job_items SET status = ?, result = ?, error = ?, updated_at = ?
+		 WHERE job_id = ? AND position = ?`)
+	if err != nil {
+		return fmt.Errorf("准备更新语句失败: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	done, failed := 0, 0
+	for _, item := range items {
+		var result interface{}
+		if item.Result != nil {
+			data, err := json.Marshal(item.Result)
+			if err != nil {
+				return fmt.Errorf("序列化估价结果失败: %w", err)
+			}
+			result = string(data)
+		}
+		if item.Status == model.JobItemDone {
+			done++
+		} else {
+			failed++
+		}
+
+		if _, err := stmt.ExecContext(ctx, item.Status, result, item.Error, now, jobID, item.Position); err != nil {
+			return fmt.Errorf("保存域名估价结果失败: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE estimation_jobs SET done = done + ?, failed = failed + ?, updated_at = ? WHERE id = ?`,
+		done, failed, now, jobID); err != nil {
+		return fmt.Errorf("更新任务进度失败: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交估价结果失败: %w", err)
+	}
+	return nil
+}
+
+// GetJobItems 按位置顺序获取任务中的所有域名及结果
+func (r *SQLJobRepository) GetJobItems(ctx context.Context, jobID string) ([]model.JobItem, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT job_id, position, domain, status, result, error
+		 FROM estimation_job_items WHERE job_id = ? ORDER BY position`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("查询任务域名失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobItems(rows)
+}
+
+// rowScanner 是 *sql.Row 和 *sql.Rows 共有的扫描方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJob 扫描一行任务数据
+func scanJob(row rowScanner) (*model.Job, error) {
+	var job model.Job
+	var errMsg sql.NullString
+	var finishedAt sql.NullTime
+	if err := row.Scan(
+		&job.ID,
+		&job.Status,
+		&job.Total,
+		&job.Done,
+		&job.Failed,
+		&errMsg,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&finishedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	job.Error = errMsg.String
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// scanJobItems 扫描任务域名数据，估价结果以JSON形式存储
+func scanJobItems(rows *sql.Rows) ([]model.JobItem, error) {
+	var items []model.JobItem
+	for rows.Next() {
+		var item model.JobItem
+		var result, errMsg sql.NullString
+		if err := rows.Scan(
+			&item.JobID,
+			&item.Position,
+			&item.Domain,
+			&item.Status,
+			&result,
+			&errMsg,
+		); err != nil {
+			return nil, fmt.Errorf("扫描任务域名行失败: %w", err)
+		}
+
+		item.Error = errMsg.String
+		if result.Valid && result.String != "" {
+			item.Result = &model.EstimationResult{}
+			if err := json.Unmarshal([]byte(result.String), item.Result); err != nil {
+				return nil, fmt.Errorf("解析 %s 的估价结果失败: %w", item.Domain, err)
+			}
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代任务域名行失败: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,88 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+func TestJobStatusGuards(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewJobRepository(testutil.OpenMigratedSQLite(t))
+
+	now := time.Now()
+	job := &model.Job{ID: "job-1", Status: model.JobStatusPending, Total: 3, CreatedAt: now, UpdatedAt: now}
+	if err := repo.CreateJob(ctx, job, []string{"a.com", "b.com", "c.com"}); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	steps := []struct {
+		status string
+		want   string
+	}{
+		{model.JobStatusRunning, model.JobStatusRunning},
+		{model.JobStatusCompleted, model.JobStatusCompleted},
+		// 已结束的任务不再改变状态
+		{model.JobStatusRunning, model.JobStatusCompleted},
+		{model.JobStatusCancelled, model.JobStatusCompleted},
+		{model.JobStatusFailed, model.JobStatusCompleted},
+	}
+	for _, step := range steps {
+		if err := repo.UpdateJobStatus(ctx, job.ID, step.status, ""); err != nil {
+			t.Fatalf("UpdateJobStatus(%s) error = %v", step.status, err)
+		}
+		got, err := repo.GetJob(ctx, job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != step.want {
+			t.Errorf("UpdateJobStatus(%s): status = %s, want %s", step.status, got.Status, step.want)
+		}
+	}
+
+	got, _ := repo.GetJob(ctx, job.ID)
+	if got.FinishedAt == nil {
+		t.Error("已完成的任务没有结束时间")
+	}
+	if unfinished, err := repo.ListUnfinishedJobs(ctx); err != nil || len(unfinished) != 0 {
+		t.Errorf("ListUnfinishedJobs() = %v, %v, want none", unfinished, err)
+	}
+	if _, err := repo.GetJob(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetJob(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestJobItemProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewJobRepository(testutil.OpenMigratedSQLite(t))
+
+	now := time.Now()
+	job := &model.Job{ID: "job-2", Status: model.JobStatusPending, Total: 3, CreatedAt: now, UpdatedAt: now}
+	if err := repo.CreateJob(ctx, job, []string{"a.com", "b.com", "c.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := repo.GetPendingJobItems(ctx, job.ID, 2)
+	if err != nil || len(pending) != 2 || pending[0].Domain != "a.com" {
+		t.Fatalf("GetPendingJobItems() = %v, %v", pending, err)
+	}
+
+	pending[0].Status, pending[0].Result = model.JobItemDone, &model.EstimationResult{Domain: "a.com", Price: 100}
+	pending[1].Status, pending[1].Error = model.JobItemFailed, "解析域名失败"
+	if err := repo.SaveJobItemResults(ctx, job.ID, pending); err != nil {
+		t.Fatalf("SaveJobItemResults() error = %v", err)
+	}
+
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil || got.Done != 1 || got.Failed != 1 {
+		t.Errorf("GetJob() = %+v, %v, want done 1 failed 1", got, err)
+	}
+	if rest, err := repo.GetPendingJobItems(ctx, job.ID, 10); err != nil || len(rest) != 1 || rest[0].Domain != "c.com" {
+		t.Errorf("GetPendingJobItems() = %v, %v, want [c.com]", rest, err)
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"domainweb/internal/model"
+)
+
+// DomainRepository 定义域名属性相关的数据访问接口
+type DomainRepository interface {
+	// GetDomainAttributes 获取所有域名属性规则，按ID排序
+	GetDomainAttributes(ctx context.Context) ([]model.DomainAttribute, error)
+	// GetAttribute 获取单个域名属性，不存在时返回 ErrNotFound
+	GetAttribute(ctx context.Context, id int64) (*model.DomainAttribute, error)
+	// CreateAttribute 保存一个新的域名属性并回填ID
+	CreateAttribute(ctx context.Context, attr *model.DomainAttribute) error
+	// UpdateAttribute 更新域名属性，不存在时返回 ErrNotFound
+	UpdateAttribute(ctx context.Context, attr *model.DomainAttribute) error
+	// DeleteAttribute 删除域名属性，不存在时返回 ErrNotFound
+	DeleteAttribute(ctx context.Context, id int64) error
+	// GetAttributesByType 根据属性类型获取域名属性
+	GetAttributesByType(ctx context.Context, attrType string) ([]model.DomainAttribute, error)
+	// GetTLDAttributes 获取所有TLD属性，键为属性值（如 com）
+	GetTLDAttributes(ctx context.Context) (map[string]model.DomainAttribute, error)
+	// GetValuationRules 获取所有动态属性估价规则，按优先级排序
+	GetValuationRules(ctx context.Context) ([]model.ValuationRule, error)
+	// GetValuationRule 获取单条估价规则，不存在时返回 ErrNotFound
+	GetValuationRule(ctx context.Context, id int64) (*model.ValuationRule, error)
+	// CreateValuationRule 保存一条新的估价规则并回填ID
+	CreateValuationRule(ctx context.Context, rule *model.ValuationRule) error
+	// DeleteValuationRule 删除估价规则，不存在时返回 ErrNotFound
+	DeleteValuationRule(ctx context.Context, id int64) error
+	// ApplyRuleChanges 在一个事务中应用域名属性和估价规则的变更，并回填新增记录的ID
+	ApplyRuleChanges(ctx context.Context, changes *model.RuleChanges) error
+	// SaveDomainInfo 保存域名基本信息，已存在时更新
+	SaveDomainInfo(ctx context.Context, domain *model.Domain) error
+}
+
+// HistoryRepository 定义查询历史相关的数据访问接口
+type HistoryRepository interface {
+	// SaveHistory 保存查询历史记录
+	SaveHistory(ctx context.Context, record *model.HistoryRecord) error
+	// SaveHistoryBatch 在一个事务中批量保存查询历史记录
+	SaveHistoryBatch(ctx context.Context, records []model.HistoryRecord) error
+	// GetHistory 获取查询历史记录，可选择按域名筛选
+	GetHistory(ctx context.Context, domain string, limit int) ([]model.HistoryRecord, error)
+	// GetHistoryRecord 获取单条查询历史记录，不存在时返回 ErrNotFound
+	GetHistoryRecord(ctx context.Context, id int64) (*model.HistoryRecord, error)
+}
+
+// RuleSetRepository 定义规则集版本相关的数据访问接口
+type RuleSetRepository interface {
+	// CreateRuleSet 保存新的规则集版本并回填版本号，校验和重复时返回错误
+	CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) error
+	// GetRuleSet 获取规则集版本及其内容，不存在时返回 ErrNotFound
+	GetRuleSet(ctx context.Context, version int64) (*model.RuleSet, error)
+	// GetRuleSetByChecksum 按内容校验和获取规则集版本（不含内容），不存在时返回 ErrNotFound
+	GetRuleSetByChecksum(ctx context.Context, checksum string) (*model.RuleSet, error)
+	// ListRuleSets 获取最近的规则集版本（不含内容），按版本号倒序排列
+	ListRuleSets(ctx context.Context, limit int) ([]model.RuleSet, error)
+}
+
+// AuditRepository 定义审计日志相关的数据访问接口
+type AuditRepository interface {
+	// SaveAuditLog 保存一条审计日志并回填ID
+	SaveAuditLog(ctx context.Context, entry *model.AuditLog) error
+	// ListAuditLogs 按条件查询审计日志，按时间倒序排列
+	ListAuditLogs(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, error)
+}
+
+// JobRepository 定义异步估价任务相关的数据访问接口
+type JobRepository interface {
+	// CreateJob 创建任务及其包含的域名
+	CreateJob(ctx context.Context, job *model.Job, domains []string) error
+	// GetJob 获取任务，不存在时返回 ErrNotFound
+	GetJob(ctx context.Context, id string) (*model.Job, error)
+	// ListUnfinishedJobs 获取所有等待执行或执行中的任务，按创建时间排序
+	ListUnfinishedJobs(ctx context.Context) ([]model.Job, error)
+	// UpdateJobStatus 更新未结束任务的状态，结束状态会同时记录结束时间
+	UpdateJobStatus(ctx context.Context, id, status, errMsg string) error
+	// GetPendingJobItems 按位置顺序获取任务中尚未估价的域名
+	GetPendingJobItems(ctx context.Context, jobID string, limit int) ([]model.JobItem, error)
+	// SaveJobItemResults 保存域名估价结果并累加任务进度
+	SaveJobItemResults(ctx context.Context, jobID string, items []model.JobItem) error
+	// GetJobItems 按位置顺序获取任务中的所有域名及结果
+	GetJobItems(ctx context.Context, jobID string) ([]model.JobItem, error)
+}
+
+// ErrNotFound 表示查询的记录不存在
+var ErrNotFound = errors.New("记录不存在")
+
+// Dialect 表示数据库方言，用于处理不同数据库之间的SQL差异
+type Dialect string
+
+const (
+	// MySQL 方言
+	MySQL Dialect = "mysql"
+	// SQLite 方言
+	SQLite Dialect = "sqlite"
+)
+
+// DriverName 返回方言对应的database/sql驱动名称
+func (d Dialect) DriverName() string {
+	if d == SQLite {
+		return "sqlite3"
+	}
+	return string(d)
+}
+
+// ParseDialect 根据配置中的驱动名称返回数据库方言
+func ParseDialect(driver string) (Dialect, error) {
+	switch driver {
+	case "mysql":
+		return MySQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return "", fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+}
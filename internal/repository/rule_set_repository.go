@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"domainweb/internal/model"
+)
+
+// SQLRuleSetRepository 基于database/sql实现RuleSetRepository，支持MySQL和SQLite
+type SQLRuleSetRepository struct {
+	db *sql.DB
+}
+
+// NewRuleSetRepository 创建一个新的SQLRuleSetRepository实例
+func NewRuleSetRepository(db *sql.DB) *SQLRuleSetRepository {
+	return &SQLRuleSetRepository{db: db}
+}
+
+// CreateRuleSet 保存新的规则集版本并回填版本号，校验和重复时返回错误
+func (r *SQLRuleSetRepository) CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) error {
+	content, err := json.Marshal(ruleSet.Content)
+	if err != nil {
+		return fmt.Errorf("序列化规则集失败: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT INTO rule_sets (checksum, author, comment, content, created_at) VALUES (?, ?, ?, ?, ?)`,
+		ruleSet.Checksum, ruleSet.Author, ruleSet.Comment, string(content), ruleSet.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存规则集失败: %w", err)
+	}
+
+	version, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取规则集版本号失败: %w", err)
+	}
+	ruleSet.Version = version
+	return nil
+}
+
+// GetRuleSet 获取规则集版本及其内容，不存在时返回 ErrNotFound
+func (r *SQLRuleSetRepository) GetRuleSet(ctx context.Context, version int64) (*model.RuleSet, error) {
+	var ruleSet model.RuleSet
+	var content string
+	err := r.db.QueryRowContext(ctx, `SELECT id, checksum, author, comment, content, created_at FROM rule_sets WHERE id = ?`, version).Scan(
+		&ruleSet.Version,
+		&ruleSet.Checksum,
+		&ruleSet.Author,
+		&ruleSet.Comment,
+		&content,
+		&ruleSet.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询规则集失败: %w", err)
+	}
+
+	ruleSet.Content = &model.RuleSetContent{}
+	if err := json.Unmarshal([]byte(content), ruleSet.Content); err != nil {
+		return nil, fmt.Errorf("解析规则集 %d 失败: %w", version, err)
+	}
+	return &ruleSet, nil
+}
+
+// GetRuleSetByChecksum 按内容校验和获取规则集版本（不含内容），不存在时返回 ErrNotFound
+func (r *SQLRuleSetRepository) GetRuleSetByChecksum(ctx context.Context, checksum string) (*model.RuleSet, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, checksum, author, comment, created_at FROM rule_sets WHERE checksum = ?`, checksum)
+	ruleSet, err := scanRuleSet(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询规则集失败: %w", err)
+	}
+	return ruleSet, nil
+}
+
+// ListRuleSets 获取最近的规则集版本（不含内容），按版本号倒序排列
+func (r *SQLRuleSetRepository) ListRuleSets(ctx context.Context, limit int) ([]model.RuleSet, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, checksum, author, comment, created_at FROM rule_sets ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询规则集失败: %w", err)
+	}
+	defer rows.Close()
+
+	var ruleSets []model.RuleSet
+	for rows.Next() {
+		ruleSet, err := scanRuleSet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描规则集行失败: %w", err)
+		}
+		ruleSets = append(ruleSets, *ruleSet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代规则集行失败: %w", err)
+	}
+	return ruleSets, nil
+}
+
+// scanRuleSet 读取一行不含内容的规则集数据
+func scanRuleSet(row rowScanner) (*model.RuleSet, error) {
+	var ruleSet model.RuleSet
+	if err := row.Scan(
+		&ruleSet.Version,
+		&ruleSet.Checksum,
+		&ruleSet.Author,
+		&ruleSet.Comment,
+		&ruleSet.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &ruleSet, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLite 打开SQLite数据库，表结构由 internal/migrate 中的迁移创建
+// path 为 ":memory:" 时使用内存数据库，适合测试和临时使用
+func OpenSQLite(path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_foreign_keys=on&_busy_timeout=5000"
+	if path == ":memory:" {
+		// 内存数据库在每个连接中独立存在，共享缓存使所有连接访问同一个数据库
+		dsn = "file::memory:?cache=shared&_foreign_keys=on"
+	}
+
+	return sql.Open(SQLite.DriverName(), dsn)
+}
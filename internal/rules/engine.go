@@ -0,0 +1,336 @@
+// Package rules 实现基于规则表的动态属性估价
+//
+// 每条规则由属性键、比较条件、估价倍数、等级增量和名称模板组成。
+// 同一属性键的规则按优先级依次匹配，第一条满足条件的规则生效，
+// 因此阈值分档只需要按从高到低（或从低到高）的顺序排列规则。
+//
+// 运算符为 expr 的规则使用 internal/expr 的条件表达式，可以同时引用域名字段和
+// 多个动态属性，如 search_volume / alexa_rank > 0.5。表达式规则按属性键分组，
+// 每组同样只有第一条满足条件的规则生效。
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"domainweb/internal/expr"
+	"domainweb/internal/model"
+)
+
+// 支持的比较运算符
+const (
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpRange        = "range"     // min_value <= 值 < max_value，任一边界为空表示不限
+	OpContains     = "contains"  // 值的文本包含比较值
+	OpNotContains  = "!contains" // 值的文本不包含比较值
+	OpExists       = "exists"    // 属性存在即匹配，通常作为最后一档
+	OpExpr         = "expr"      // 条件表达式为真时匹配，属性键仅用于分组
+)
+
+// Operators 按说明顺序列出所有运算符
+var Operators = []string{
+	OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpEqual, OpNotEqual,
+	OpRange, OpContains, OpNotContains, OpExists, OpExpr,
+}
+
+// Match 表示一条规则与一个动态属性的匹配结果
+type Match struct {
+	Rule        model.ValuationRule
+	Key         string // 实际匹配的属性键，如 related_domain_net
+	Value       string // 属性值的文本形式，表达式规则为同名变量的值（不存在时为空）
+	Name        string // 展开模板后的属性名称
+	Description string // 展开模板后的属性描述
+}
+
+// Engine 根据一组估价规则匹配动态属性
+type Engine struct {
+	rules    []model.ValuationRule
+	programs []*expr.Program // 与 rules 一一对应，非表达式规则为nil
+}
+
+// NewEngine 校验规则并创建Engine，未启用的规则会被忽略
+// dynamicKeys 为数据源声明的动态属性键，用于编译表达式规则
+func NewEngine(rules []model.ValuationRule, dynamicKeys []string) (*Engine, error) {
+	enabled := make([]model.ValuationRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].Priority < enabled[j].Priority
+	})
+
+	programs := make([]*expr.Program, len(enabled))
+	for i, rule := range enabled {
+		program, err := validate(rule, dynamicKeys)
+		if err != nil {
+			return nil, fmt.Errorf("估价规则 %d 无效: %w", rule.ID, err)
+		}
+		programs[i] = program
+	}
+	return &Engine{rules: enabled, programs: programs}, nil
+}
+
+// Rules 返回按优先级排序的已启用规则
+func (e *Engine) Rules() []model.ValuationRule {
+	return e.rules
+}
+
+// Validate 校验单条规则的属性键、运算符和比较值，表达式规则会按 dynamicKeys 编译表达式
+func Validate(rule model.ValuationRule, dynamicKeys []string) error {
+	_, err := validate(rule, dynamicKeys)
+	return err
+}
+
+// validate 校验规则，表达式规则同时返回编译后的表达式
+func validate(rule model.ValuationRule, dynamicKeys []string) (*expr.Program, error) {
+	key := rule.AttributeKey
+	if key == "" {
+		return nil, fmt.Errorf("属性键不能为空")
+	}
+	if strings.Contains(strings.TrimSuffix(key, "*"), "*") {
+		return nil, fmt.Errorf("属性键 %s 中的 * 只能出现在末尾", key)
+	}
+	if strings.TrimSpace(rule.Label) == "" {
+		return nil, fmt.Errorf("属性名称不能为空")
+	}
+	if rule.PriceFactor <= 0 {
+		return nil, fmt.Errorf("估价倍数必须大于0")
+	}
+
+	switch rule.Operator {
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		if _, ok := toFloat(rule.Value); !ok {
+			return nil, fmt.Errorf("运算符 %s 的比较值必须是数字: %q", rule.Operator, rule.Value)
+		}
+	case OpEqual, OpNotEqual, OpContains, OpNotContains:
+		if rule.Value == "" {
+			return nil, fmt.Errorf("运算符 %s 的比较值不能为空", rule.Operator)
+		}
+	case OpRange:
+		if rule.MinValue == nil && rule.MaxValue == nil {
+			return nil, fmt.Errorf("range 运算至少需要设置下限或上限")
+		}
+		if rule.MinValue != nil && rule.MaxValue != nil && *rule.MinValue >= *rule.MaxValue {
+			return nil, fmt.Errorf("range 的下限 %g 必须小于上限 %g", *rule.MinValue, *rule.MaxValue)
+		}
+	case OpExists:
+	case OpExpr:
+		if strings.Contains(key, "*") {
+			return nil, fmt.Errorf("表达式规则的属性键不能包含 *")
+		}
+		program, err := Compile(rule.Expression, dynamicKeys)
+		if err != nil {
+			return nil, fmt.Errorf("表达式无效: %w", err)
+		}
+		return program, nil
+	default:
+		return nil, fmt.Errorf("不支持的运算符: %s", rule.Operator)
+	}
+	return nil, nil
+}
+
+// Evaluate 为每个动态属性找到第一条满足条件的规则，并按属性键分组执行表达式规则
+// 结果按属性键对应的最小规则优先级排序，优先级相同时按属性键排序
+// now 是表达式中注册年数和到期天数的参照时间
+func (e *Engine) Evaluate(domain *model.Domain, attrs map[string]interface{}, now time.Time) []Match {
+	// 不含后缀的域名主体，用于展开模板中的 {name}
+	name := strings.TrimSuffix(domain.Name, "."+domain.TLD)
+
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var matches []ordered
+	for _, key := range keys {
+		value := attrs[key]
+		if value == nil {
+			continue
+		}
+
+		order, found := 0, false
+		for _, rule := range e.rules {
+			if rule.Operator == OpExpr {
+				continue
+			}
+			suffix, ok := matchKey(rule.AttributeKey, key)
+			if !ok {
+				continue
+			}
+			if !found {
+				order, found = rule.Priority, true
+			}
+			if !matchValue(rule, value) {
+				continue
+			}
+
+			matches = append(matches, newMatch(rule, key, fmt.Sprint(value), suffix, name, order))
+			break
+		}
+	}
+
+	matches = append(matches, e.evaluateExpressions(domain, attrs, name, now)...)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].order < matches[j].order
+	})
+	result := make([]Match, len(matches))
+	for i, m := range matches {
+		result[i] = m.match
+	}
+	return result
+}
+
+// ordered 是带排序依据的匹配结果
+type ordered struct {
+	match Match
+	order int
+}
+
+// evaluateExpressions 执行表达式规则，同一属性键的规则中第一条结果为真的生效
+// 表达式执行出错（如引用的动态属性缺失、除数为0）时视为不满足条件
+func (e *Engine) evaluateExpressions(domain *model.Domain, attrs map[string]interface{}, name string, now time.Time) []ordered {
+	var env map[string]interface{}
+	var matches []ordered
+	order := make(map[string]int)
+	matched := make(map[string]bool)
+	for i, rule := range e.rules {
+		if rule.Operator != OpExpr {
+			continue
+		}
+		key := rule.AttributeKey
+		if _, ok := order[key]; !ok {
+			order[key] = rule.Priority
+		}
+		if matched[key] {
+			continue
+		}
+
+		if env == nil {
+			env = Env(domain, attrs, now)
+		}
+		ok, err := e.programs[i].Eval(env)
+		if err != nil || !ok {
+			continue
+		}
+
+		value := ""
+		if v, ok := env[key]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+		matched[key] = true
+		matches = append(matches, newMatch(rule, key, value, "", name, order[key]))
+	}
+
+	// 与动态属性的匹配结果一致，同一优先级按属性键排序
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].match.Key < matches[j].match.Key
+	})
+	return matches
+}
+
+// newMatch 展开规则的名称和描述模板
+func newMatch(rule model.ValuationRule, key, value, suffix, name string, order int) ordered {
+	replacer := strings.NewReplacer("{value}", value, "{key}", key, "{suffix}", suffix, "{name}", name)
+	description := replacer.Replace(rule.Description)
+	if description == "" {
+		description = replacer.Replace(rule.Label)
+	}
+	return ordered{
+		match: Match{
+			Rule:        rule,
+			Key:         key,
+			Value:       value,
+			Name:        replacer.Replace(rule.Label),
+			Description: description,
+		},
+		order: order,
+	}
+}
+
+// matchKey 判断规则的属性键是否匹配，前缀匹配时返回 * 对应的部分
+func matchKey(pattern, key string) (string, bool) {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		if strings.HasPrefix(key, prefix) {
+			return strings.TrimPrefix(key, prefix), true
+		}
+		return "", false
+	}
+	return "", pattern == key
+}
+
+// matchValue 判断属性值是否满足规则的比较条件
+func matchValue(rule model.ValuationRule, value interface{}) bool {
+	switch rule.Operator {
+	case OpExists:
+		return true
+	case OpContains:
+		return strings.Contains(fmt.Sprint(value), rule.Value)
+	case OpNotContains:
+		return !strings.Contains(fmt.Sprint(value), rule.Value)
+	case OpEqual, OpNotEqual:
+		equal := fmt.Sprint(value) == rule.Value
+		if v, ok := toFloat(value); ok {
+			if target, ok := toFloat(rule.Value); ok {
+				equal = v == target
+			}
+		}
+		return equal == (rule.Operator == OpEqual)
+	}
+
+	v, ok := toFloat(value)
+	if !ok {
+		return false
+	}
+
+	if rule.Operator == OpRange {
+		return (rule.MinValue == nil || v >= *rule.MinValue) && (rule.MaxValue == nil || v < *rule.MaxValue)
+	}
+
+	target, ok := toFloat(rule.Value)
+	if !ok {
+		return false
+	}
+	switch rule.Operator {
+	case OpLess:
+		return v < target
+	case OpLessEqual:
+		return v <= target
+	case OpGreater:
+		return v > target
+	case OpGreaterEqual:
+		return v >= target
+	}
+	return false
+}
+
+// toFloat 将数字或数字字符串转换为float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
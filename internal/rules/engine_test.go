@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"domainweb/internal/model"
+)
+
+// rule 创建一条启用的比较规则
+func rule(id int64, key, op, value string, priority int, label string) model.ValuationRule {
+	return model.ValuationRule{
+		ID: id, AttributeKey: key, Operator: op, Value: value,
+		PriceFactor: 1, Label: label, Priority: priority, Enabled: true,
+	}
+}
+
+// between 创建一条 range 规则，lo 或 hi 为空表示不限
+func between(id int64, key string, lo, hi *float64, priority int, label string) model.ValuationRule {
+	r := rule(id, key, OpRange, "", priority, label)
+	r.MinValue, r.MaxValue = lo, hi
+	return r
+}
+
+func float(v float64) *float64 { return &v }
+
+// 按排名分档，顺序打乱以验证按优先级排序
+var rankTiers = []model.ValuationRule{
+	rule(3, "alexa_rank", OpExists, "", 30, "排名靠后"),
+	rule(1, "alexa_rank", OpLess, "1000", 10, "排名顶尖"),
+	rule(2, "alexa_rank", OpLessEqual, "100000", 20, "排名一般"),
+}
+
+func TestEvaluate(t *testing.T) {
+	domain := &model.Domain{Name: "abc.com", TLD: "com", Length: 3}
+
+	disabled := rule(4, "alexa_rank", OpExists, "", 1, "已禁用")
+	disabled.Enabled = false
+
+	tests := []struct {
+		name  string
+		rules []model.ValuationRule
+		attrs map[string]interface{}
+		want  []string
+	}{
+		{"最高一档", rankTiers, map[string]interface{}{"alexa_rank": 500}, []string{"排名顶尖"}},
+		{"中间一档", rankTiers, map[string]interface{}{"alexa_rank": 1000}, []string{"排名一般"}},
+		{"上界含等号", rankTiers, map[string]interface{}{"alexa_rank": 100000}, []string{"排名一般"}},
+		{"最后一档", rankTiers, map[string]interface{}{"alexa_rank": 100001}, []string{"排名靠后"}},
+		{"数字字符串", rankTiers, map[string]interface{}{"alexa_rank": " 800 "}, []string{"排名顶尖"}},
+		{"非数字落入 exists", rankTiers, map[string]interface{}{"alexa_rank": "未知"}, []string{"排名靠后"}},
+		{"值为空时跳过", rankTiers, map[string]interface{}{"alexa_rank": nil}, nil},
+		{"没有规则的属性", rankTiers, map[string]interface{}{"search_volume": 100}, nil},
+		{"禁用的规则被忽略", append([]model.ValuationRule{disabled}, rankTiers...),
+			map[string]interface{}{"alexa_rank": 500}, []string{"排名顶尖"}},
+
+		{"前缀键", []model.ValuationRule{
+			rule(1, "related_domain_*", OpContains, "已注册", 10, "{suffix}已注册"),
+			rule(2, "related_domain_*", OpExists, "", 20, "{suffix}未注册"),
+		}, map[string]interface{}{
+			"related_domain_org": "已注册", "related_domain_net": "未注册", "related": "已注册",
+		}, []string{"net未注册", "org已注册"}},
+
+		{"range 下限包含", []model.ValuationRule{between(1, "volume", float(10), float(20), 10, "中等")},
+			map[string]interface{}{"volume": 10}, []string{"中等"}},
+		{"range 上限不包含", []model.ValuationRule{between(1, "volume", float(10), float(20), 10, "中等")},
+			map[string]interface{}{"volume": 20}, nil},
+		{"range 上限以内", []model.ValuationRule{between(1, "volume", float(10), float(20), 10, "中等")},
+			map[string]interface{}{"volume": 19.99}, []string{"中等"}},
+		{"range 低于下限", []model.ValuationRule{between(1, "volume", float(10), float(20), 10, "中等")},
+			map[string]interface{}{"volume": 9}, nil},
+		{"range 不限上限", []model.ValuationRule{between(1, "volume", float(10), nil, 10, "较高")},
+			map[string]interface{}{"volume": 1e9}, []string{"较高"}},
+		{"range 不限下限", []model.ValuationRule{between(1, "volume", nil, float(10), 10, "较低")},
+			map[string]interface{}{"volume": -5}, []string{"较低"}},
+
+		{"按规则优先级排列结果", append([]model.ValuationRule{
+			rule(5, "volume", OpGreater, "0", 5, "有搜索量"),
+		}, rankTiers...), map[string]interface{}{"alexa_rank": 500, "volume": 3}, []string{"有搜索量", "排名顶尖"}},
+
+		{"相等与不等", []model.ValuationRule{
+			rule(1, "registered", OpEqual, "true", 10, "已注册"),
+			rule(2, "count", OpNotEqual, "0", 10, "有数量"),
+		}, map[string]interface{}{"registered": true, "count": 0.0}, []string{"已注册"}},
+	}
+	for _, tt := range tests {
+		engine, err := NewEngine(tt.rules, []string{"alexa_rank", "volume"})
+		if err != nil {
+			t.Fatalf("%s: NewEngine() error = %v", tt.name, err)
+		}
+		var got []string
+		for _, m := range engine.Evaluate(domain, tt.attrs, time.Now()) {
+			got = append(got, m.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Evaluate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateTemplates(t *testing.T) {
+	r := rule(1, "related_domain_*", OpExists, "", 10, "{suffix}相关")
+	r.Description = "{name} 的 {suffix} 域名: {value}（{key}）"
+	engine, err := NewEngine([]model.ValuationRule{r}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := engine.Evaluate(&model.Domain{Name: "abc.com.cn", TLD: "com.cn"},
+		map[string]interface{}{"related_domain_net": "未注册"}, time.Now())
+	if len(matches) != 1 {
+		t.Fatalf("Evaluate() = %v", matches)
+	}
+	m := matches[0]
+	if m.Key != "related_domain_net" || m.Value != "未注册" || m.Name != "net相关" ||
+		m.Description != "abc 的 net 域名: 未注册（related_domain_net）" {
+		t.Errorf("Match = %+v", m)
+	}
+}
+
+func TestEvaluateExpressions(t *testing.T) {
+	short := rule(1, "short_rank", OpExpr, "", 10, "短且排名高")
+	short.Expression = `length <= 4 && alexa_rank < 1000`
+	fallback := rule(2, "short_rank", OpExpr, "", 20, "短域名")
+	fallback.Expression = `length <= 4`
+	engine, err := NewEngine([]model.ValuationRule{fallback, short}, []string{"alexa_rank"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain := &model.Domain{Name: "abc.com", TLD: "com", Length: 3}
+
+	tests := []struct {
+		attrs map[string]interface{}
+		want  []string
+	}{
+		{map[string]interface{}{"alexa_rank": 500}, []string{"短且排名高"}},
+		{map[string]interface{}{"alexa_rank": 5000}, []string{"短域名"}},
+		// 引用的动态属性缺失时表达式视为不满足
+		{map[string]interface{}{}, []string{"短域名"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range engine.Evaluate(domain, tt.attrs, time.Now()) {
+			got = append(got, m.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Evaluate(%v) = %v, want %v", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	expression := rule(1, "x", OpExpr, "", 10, "表达式")
+	expression.Expression = "unknown > 1"
+
+	tests := []struct {
+		rule model.ValuationRule
+		want string
+	}{
+		{rule(1, "", OpExists, "", 10, "空键"), "属性键不能为空"},
+		{rule(1, "a*b", OpExists, "", 10, "星号"), "只能出现在末尾"},
+		{rule(1, "a", OpExists, "", 10, " "), "属性名称不能为空"},
+		{rule(1, "a", "~", "", 10, "未知"), "不支持的运算符"},
+		{rule(1, "a", OpLess, "abc", 10, "非数字"), "必须是数字"},
+		{rule(1, "a", OpContains, "", 10, "空值"), "不能为空"},
+		{between(1, "a", nil, nil, 10, "无边界"), "至少需要设置"},
+		{between(1, "a", float(5), float(5), 10, "边界相等"), "必须小于上限"},
+		{expression, "表达式无效"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.rule, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) error = %v, want error containing %q", tt.rule, err, tt.want)
+		}
+	}
+
+	zero := rule(1, "a", OpExists, "", 10, "零倍数")
+	zero.PriceFactor = 0
+	if _, err := NewEngine([]model.ValuationRule{zero}, nil); err == nil {
+		t.Error("NewEngine() error = nil, want error for a zero price factor")
+	}
+	zero.Enabled = false
+	if _, err := NewEngine([]model.ValuationRule{zero}, nil); err != nil {
+		t.Errorf("NewEngine() error = %v, 未启用的规则不参与校验", err)
+	}
+}
@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"domainweb/internal/expr"
+	"domainweb/internal/model"
+)
+
+// DomainVariables 列出表达式规则可以使用的域名字段及其类型
+var DomainVariables = expr.Vars{
+	"name":           expr.String, // 规范化的域名，如 xn--fiq228c.com
+	"unicode_name":   expr.String, // Unicode形式的域名，如 中文.com
+	"label":          expr.String, // 不含后缀的域名主体（Unicode形式），如 中文
+	"tld":            expr.String, // 有效顶级域名，如 com、com.cn
+	"subdomain":      expr.String, // 子域名，如 www
+	"length":         expr.Number, // 域名主体的长度
+	"structure":      expr.String, // 域名结构，如 纯字母
+	"patterns":       expr.List,   // 号码形态，如 ["AABB", "含8"]
+	"syllables":      expr.List,   // 全拼的拼音音节，如 ["tao", "bao"]，不是全拼时为空
+	"syllable_count": expr.Number, // 全拼的音节数，不是全拼时为0
+	"register_date":  expr.String, // 注册日期，格式为 2006-01-02
+	"expire_date":    expr.String, // 到期日期，格式为 2006-01-02
+	"age_years":      expr.Number, // 注册年数
+	"expire_days":    expr.Number, // 距离到期的天数
+}
+
+// scope 是表达式规则的编译作用域，包括域名字段和数据源声明的动态属性键
+// 动态属性的类型在运行时才能确定；以 * 结尾的属性键匹配所有以该前缀开头的变量
+type scope struct {
+	dynamicKeys []string
+}
+
+// Lookup 查找变量的类型，域名字段优先于同名的动态属性
+func (s scope) Lookup(name string) (expr.Type, bool) {
+	if t, ok := DomainVariables[name]; ok {
+		return t, true
+	}
+	for _, key := range s.dynamicKeys {
+		if _, ok := matchKey(key, name); ok {
+			return expr.Any, true
+		}
+	}
+	return expr.Any, false
+}
+
+// Compile 按域名字段和动态属性键编译条件表达式
+func Compile(source string, dynamicKeys []string) (*expr.Program, error) {
+	return expr.Compile(source, scope{dynamicKeys: dynamicKeys})
+}
+
+// Env 生成表达式规则执行时的变量值，包含动态属性和域名字段
+// now 是计算注册年数和到期天数的参照时间
+func Env(domain *model.Domain, attrs map[string]interface{}, now time.Time) map[string]interface{} {
+	env := make(map[string]interface{}, len(attrs)+len(DomainVariables))
+	for key, value := range attrs {
+		env[key] = value
+	}
+
+	label := domain.UnicodeName
+	if i := strings.Index(label, "."); i >= 0 {
+		label = label[:i]
+	}
+	env["name"] = domain.Name
+	env["unicode_name"] = domain.UnicodeName
+	env["label"] = label
+	env["tld"] = domain.TLD
+	env["subdomain"] = domain.Subdomain
+	env["length"] = domain.Length
+	env["structure"] = domain.Structure
+	env["patterns"] = domain.Patterns
+	env["syllables"] = domain.Syllables
+	env["syllable_count"] = len(domain.Syllables)
+	env["register_date"] = domain.RegisterDate.Format("2006-01-02")
+	env["expire_date"] = domain.ExpireDate.Format("2006-01-02")
+	env["age_years"] = math.Floor(now.Sub(domain.RegisterDate).Hours() / 24 / 365)
+	env["expire_days"] = math.Floor(domain.ExpireDate.Sub(now).Hours() / 24)
+	return env
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+)
+
+// AttributeCatalog 在内存中缓存域名属性和估价规则，避免每次估价都查询数据库
+// 缓存超过刷新间隔或调用 Invalidate 后失效，下次读取时重新加载；
+// 其他进程（如命令行）直接修改数据库时，最迟在一个刷新间隔后生效
+type AttributeCatalog struct {
+	repo    repository.DomainRepository
+	refresh time.Duration    // 刷新间隔，0表示只在 Invalidate 后重新加载
+	now     func() time.Time // 记录加载时间和判断过期的时钟
+
+	mu         sync.RWMutex
+	entry      *catalogEntry
+	generation int64      // 每次 Invalidate 递增，用于丢弃失效前开始的加载结果
+	loadMu     sync.Mutex // 同一时间只有一个请求重新加载
+
+	hits       atomic.Int64
+	misses     atomic.Int64
+	loads      atomic.Int64
+	loadErrors atomic.Int64
+}
+
+// catalogEntry 是一次加载的结果，加载后不再修改，可以被多个估价共享
+type catalogEntry struct {
+	attributes     []model.DomainAttribute
+	valuationRules []model.ValuationRule
+	loadedAt       time.Time
+}
+
+// CatalogStats 是属性缓存的统计信息
+type CatalogStats struct {
+	Hits            int64     `json:"hits"`            // 命中缓存的次数
+	Misses          int64     `json:"misses"`          // 缓存为空或已失效的次数
+	Loads           int64     `json:"loads"`           // 从数据库加载的次数
+	LoadErrors      int64     `json:"loadErrors"`      // 加载失败的次数
+	Attributes      int       `json:"attributes"`      // 缓存的域名属性数量
+	ValuationRules  int       `json:"valuationRules"`  // 缓存的估价规则数量
+	LoadedAt        time.Time `json:"loadedAt"`        // 最近一次加载的时间，缓存为空时为零值
+	RefreshInterval int       `json:"refreshInterval"` // 刷新间隔（秒）
+}
+
+// NewAttributeCatalog 创建一个新的AttributeCatalog实例，首次读取时加载
+func NewAttributeCatalog(repo repository.DomainRepository, refresh time.Duration) *AttributeCatalog {
+	return &AttributeCatalog{repo: repo, refresh: refresh, now: time.Now}
+}
+
+// Invalidate 使缓存失效，修改域名属性或估价规则后调用
+func (c *AttributeCatalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entry = nil
+	c.generation++
+}
+
+// Stats 返回缓存的统计信息
+func (c *AttributeCatalog) Stats() CatalogStats {
+	stats := CatalogStats{
+		Hits:            c.hits.Load(),
+		Misses:          c.misses.Load(),
+		Loads:           c.loads.Load(),
+		LoadErrors:      c.loadErrors.Load(),
+		RefreshInterval: int(c.refresh / time.Second),
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.entry != nil {
+		stats.Attributes = len(c.entry.attributes)
+		stats.ValuationRules = len(c.entry.valuationRules)
+		stats.LoadedAt = c.entry.loadedAt
+	}
+	return stats
+}
+
+// get 返回缓存的域名属性和估价规则，缓存为空或过期时从数据库加载
+// 过期后加载失败时继续使用过期的内容，调用 Invalidate 后加载失败或 ctx 已取消则返回错误
+func (c *AttributeCatalog) get(ctx context.Context) (*catalogEntry, error) {
+	entry, _ := c.current()
+	if c.fresh(entry) {
+		c.hits.Add(1)
+		return entry, nil
+	}
+	c.misses.Add(1)
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	// 等待期间其他请求可能已经加载完成
+	entry, generation := c.current()
+	if c.fresh(entry) {
+		return entry, nil
+	}
+
+	loaded, err := c.load(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.loadErrors.Add(1)
+		if entry != nil {
+			log.Printf("刷新域名属性缓存失败，继续使用 %s 加载的内容: %v", entry.loadedAt.Format(time.RFC3339), err)
+			return entry, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entry = loaded
+	}
+	c.mu.Unlock()
+	return loaded, nil
+}
+
+// current 返回当前缓存的内容和失效计数
+func (c *AttributeCatalog) current() (*catalogEntry, int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.entry, c.generation
+}
+
+// fresh 判断缓存的内容是否仍然有效
+func (c *AttributeCatalog) fresh(entry *catalogEntry) bool {
+	return entry != nil && (c.refresh <= 0 || c.now().Sub(entry.loadedAt) < c.refresh)
+}
+
+// load 从存储库读取所有域名属性和估价规则
+func (c *AttributeCatalog) load(ctx context.Context) (*catalogEntry, error) {
+	c.loads.Add(1)
+	attributes, err := c.repo.GetDomainAttributes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取域名属性失败: %w", err)
+	}
+	valuationRules, err := c.repo.GetValuationRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取估价规则失败: %w", err)
+	}
+	return &catalogEntry{
+		attributes:     attributes,
+		valuationRules: valuationRules,
+		loadedAt:       c.now(),
+	}, nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"domainweb/internal/config"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+// failingDomainRepository 在 fail 为 true 时读取域名属性失败
+type failingDomainRepository struct {
+	repository.DomainRepository
+	fail bool
+}
+
+func (r *failingDomainRepository) GetDomainAttributes(ctx context.Context) ([]model.DomainAttribute, error) {
+	if r.fail {
+		return nil, errors.New("数据库不可用")
+	}
+	return r.DomainRepository.GetDomainAttributes(ctx)
+}
+
+// newTestCatalog 创建使用可调时钟的属性缓存，返回的 advance 将时钟向前拨动
+func newTestCatalog(db *sql.DB, refresh time.Duration) (*AttributeCatalog, *failingDomainRepository, func(time.Duration)) {
+	repo := &failingDomainRepository{DomainRepository: repository.NewDomainRepository(db, repository.SQLite)}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	catalog := NewAttributeCatalog(repo, refresh)
+	catalog.now = func() time.Time { return now }
+	return catalog, repo, func(d time.Duration) { now = now.Add(d) }
+}
+
+// newRule 创建一条可以保存的估价规则
+func newRule(label string) *model.ValuationRule {
+	return &model.ValuationRule{AttributeKey: "alexa_rank", Operator: "exists", PriceFactor: 1, Label: label, Priority: 999, Enabled: true}
+}
+
+func TestAttributeCatalogRefresh(t *testing.T) {
+	ctx := context.Background()
+	catalog, repo, advance := newTestCatalog(testutil.OpenMigratedSQLite(t), time.Minute)
+
+	first, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	rules := len(first.valuationRules)
+
+	// 其他进程直接修改数据库，刷新间隔内仍使用缓存
+	if err := repo.CreateValuationRule(ctx, newRule("直接修改")); err != nil {
+		t.Fatal(err)
+	}
+	advance(59 * time.Second)
+	if entry, _ := catalog.get(ctx); entry != first {
+		t.Error("刷新间隔内重新加载了缓存")
+	}
+
+	// 超过刷新间隔后重新加载
+	advance(time.Second)
+	entry, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry == first || len(entry.valuationRules) != rules+1 {
+		t.Errorf("过期后有 %d 条规则，want %d", len(entry.valuationRules), rules+1)
+	}
+
+	stats := catalog.Stats()
+	want := CatalogStats{
+		Hits: 1, Misses: 2, Loads: 2,
+		Attributes: len(entry.attributes), ValuationRules: rules + 1,
+		LoadedAt: time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC), RefreshInterval: 60,
+	}
+	if stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestAttributeCatalogNoRefresh(t *testing.T) {
+	ctx := context.Background()
+	catalog, _, advance := newTestCatalog(testutil.OpenMigratedSQLite(t), 0)
+
+	first, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 刷新间隔为0时只在 Invalidate 后重新加载
+	advance(365 * 24 * time.Hour)
+	if entry, _ := catalog.get(ctx); entry != first {
+		t.Error("没有调用 Invalidate 时重新加载了缓存")
+	}
+	catalog.Invalidate()
+	if entry, _ := catalog.get(ctx); entry == first {
+		t.Error("Invalidate 后没有重新加载")
+	}
+	if stats := catalog.Stats(); stats.Hits != 1 || stats.Misses != 2 || stats.Loads != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestAttributeCatalogInvalidatedByRuleChanges(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.OpenMigratedSQLite(t)
+	catalog, repo, _ := newTestCatalog(db, time.Hour)
+
+	cfg := config.Default()
+	audit := NewAuditService(repository.NewAuditRepository(db))
+	ruleSets := NewRuleSetService(repository.NewRuleSetRepository(db), catalog, audit, cfg.Estimation)
+	providers := NewProviderRegistry()
+	_ = providers.Register(fakeProvider("rank", []string{"alexa_rank"}, nil, nil, nil))
+	rules := NewRuleService(repo, providers, ruleSets, audit)
+
+	before, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 通过服务修改规则后立即生效，不必等待刷新间隔
+	rule := newRule("新增规则")
+	if err := rules.Create(ctx, rule, Change{Source: model.AuditSourceAPI}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	entry, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.valuationRules) != len(before.valuationRules)+1 {
+		t.Errorf("新增规则后缓存中有 %d 条规则，want %d", len(entry.valuationRules), len(before.valuationRules)+1)
+	}
+
+	if err := rules.Delete(ctx, rule.ID, Change{Source: model.AuditSourceAPI}); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := catalog.get(ctx); len(entry.valuationRules) != len(before.valuationRules) {
+		t.Errorf("删除规则后缓存中有 %d 条规则，want %d", len(entry.valuationRules), len(before.valuationRules))
+	}
+}
+
+func TestAttributeCatalogLoadError(t *testing.T) {
+	ctx := context.Background()
+	catalog, repo, advance := newTestCatalog(testutil.OpenMigratedSQLite(t), time.Minute)
+
+	first, err := catalog.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 过期后加载失败时继续使用过期的内容
+	repo.fail = true
+	advance(time.Hour)
+	if entry, err := catalog.get(ctx); err != nil || entry != first {
+		t.Errorf("get() = %p, %v, want stale entry", entry, err)
+	}
+
+	// Invalidate 后加载失败返回错误
+	catalog.Invalidate()
+	if _, err := catalog.get(ctx); err == nil {
+		t.Error("get() error = nil, want error")
+	}
+	if stats := catalog.Stats(); stats.LoadErrors != 2 || stats.Attributes != 0 || !stats.LoadedAt.IsZero() {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	repo.fail = false
+	if _, err := catalog.get(ctx); err != nil {
+		t.Errorf("恢复后 get() error = %v", err)
+	}
+}
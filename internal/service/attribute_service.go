@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"domainweb/internal/model"
+	"domainweb/internal/pattern"
+	"domainweb/internal/pinyin"
+	"domainweb/internal/repository"
+)
+
+var (
+	// ErrAttributeNotFound 表示域名属性不存在
+	ErrAttributeNotFound = errors.New("域名属性不存在")
+	// ErrInvalidAttribute 表示域名属性未通过校验
+	ErrInvalidAttribute = errors.New("域名属性无效")
+)
+
+// AttributeTypes 列出可选的域名属性类型
+var AttributeTypes = []string{model.AttributeTypeBase, model.AttributeTypeOther}
+
+// 估价倍数和等级增量以 DECIMAL(10, 2) 保存
+const maxFactor = 1e8
+
+// AttributePreview 表示修改域名属性前后同一域名的估价结果
+type AttributePreview struct {
+	Domain string
+	Before *model.EstimationResult
+	After  *model.EstimationResult
+}
+
+// AttributeService 管理域名属性，保存前校验属性是否能被估价逻辑正确使用
+type AttributeService struct {
+	repo          repository.DomainRepository
+	domainService *DomainService
+	ruleSets      *RuleSetService
+	audit         *AuditService
+}
+
+// NewAttributeService 创建一个新的AttributeService实例
+func NewAttributeService(repo repository.DomainRepository, domainService *DomainService, ruleSets *RuleSetService, audit *AuditService) *AttributeService {
+	return &AttributeService{repo: repo, domainService: domainService, ruleSets: ruleSets, audit: audit}
+}
+
+// List 获取所有域名属性
+func (s *AttributeService) List(ctx context.Context) ([]model.DomainAttribute, error) {
+	return s.repo.GetDomainAttributes(ctx)
+}
+
+// Get 获取单个域名属性
+func (s *AttributeService) Get(ctx context.Context, id int64) (*model.DomainAttribute, error) {
+	attr, err := s.repo.GetAttribute(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrAttributeNotFound
+	}
+	return attr, err
+}
+
+// Create 校验并保存一个新的域名属性，记录审计日志并创建新的规则集版本
+func (s *AttributeService) Create(ctx context.Context, attr *model.DomainAttribute, change Change) error {
+	attr.ID = 0
+	if err := s.Validate(ctx, attr); err != nil {
+		return err
+	}
+	s.ruleSets.checkpoint(ctx)
+	if err := s.repo.CreateAttribute(ctx, attr); err != nil {
+		return err
+	}
+	s.audit.record(change, model.AuditEntityAttribute, attr.ID, attr.AttributeName, model.AuditActionCreate, nil, attr)
+	s.ruleSets.record(change, fmt.Sprintf("新增域名属性 %d %s", attr.ID, attr.AttributeName))
+	return nil
+}
+
+// Update 校验并更新域名属性，属性有变化时记录审计日志并创建新的规则集版本
+func (s *AttributeService) Update(ctx context.Context, attr *model.DomainAttribute, change Change) error {
+	if err := s.Validate(ctx, attr); err != nil {
+		return err
+	}
+	before, err := s.Get(ctx, attr.ID)
+	if err != nil {
+		return err
+	}
+	if before.Equal(*attr) {
+		return nil
+	}
+
+	s.ruleSets.checkpoint(ctx)
+	err = s.repo.UpdateAttribute(ctx, attr)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAttributeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.audit.record(change, model.AuditEntityAttribute, attr.ID, attr.AttributeName, model.AuditActionUpdate, before, attr)
+	s.ruleSets.record(change, fmt.Sprintf("修改域名属性 %d %s", attr.ID, attr.AttributeName))
+	return nil
+}
+
+// Delete 删除域名属性，记录审计日志并创建新的规则集版本
+func (s *AttributeService) Delete(ctx context.Context, id int64, change Change) error {
+	before, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.ruleSets.checkpoint(ctx)
+	err = s.repo.DeleteAttribute(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAttributeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.audit.record(change, model.AuditEntityAttribute, id, before.AttributeName, model.AuditActionDelete, before, nil)
+	s.ruleSets.record(change, fmt.Sprintf("删除域名属性 %d %s", id, before.AttributeName))
+	return nil
+}
+
+// Preview 按保存 attr 后的属性估价 domain，attr.ID 为0时视为新增属性
+func (s *AttributeService) Preview(ctx context.Context, domain string, attr model.DomainAttribute) (*AttributePreview, error) {
+	if err := s.Validate(ctx, &attr); err != nil {
+		return nil, err
+	}
+
+	before, after, err := s.domainService.PreviewEstimate(ctx, domain, func(attrs []model.DomainAttribute) []model.DomainAttribute {
+		for i := range attrs {
+			if attrs[i].ID == attr.ID {
+				attrs[i] = attr
+				return attrs
+			}
+		}
+		return append(attrs, attr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AttributePreview{Domain: domain, Before: before, After: after}, nil
+}
+
+// Validate 规范化并校验域名属性
+// 估价时基础属性按名称区分用途：以“后缀”结尾的是TLD属性，包含“长度”的是长度属性（如 4位长度、9位及以上长度），
+// 包含“结构”的是结构属性，包含“形态”的是形态属性（如 AABB形态），因此名称不符合这些约定的基础属性不会生效
+func (s *AttributeService) Validate(ctx context.Context, attr *model.DomainAttribute) error {
+	if problems := attributeProblems(attr); len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAttribute, strings.Join(problems, "; "))
+	}
+
+	// 同一后缀、长度、结构或形态只能有一个属性，否则估价时只有其中一个生效
+	if attributeKind(attr.AttributeName) != "" && attr.AttributeType == model.AttributeTypeBase {
+		attrs, err := s.repo.GetDomainAttributes(ctx)
+		if err != nil {
+			return err
+		}
+		others := attrs[:0]
+		for _, other := range attrs {
+			if other.ID != attr.ID {
+				others = append(others, other)
+			}
+		}
+		if msg := duplicateAttribute(attr, others); msg != "" {
+			return fmt.Errorf("%w: %s", ErrInvalidAttribute, msg)
+		}
+	}
+	return nil
+}
+
+// attributeProblems 规范化域名属性并返回所有不符合要求的地方
+func attributeProblems(attr *model.DomainAttribute) []string {
+	attr.AttributeName = strings.TrimSpace(attr.AttributeName)
+	attr.AttributeType = strings.TrimSpace(attr.AttributeType)
+	attr.AttributeValue = strings.TrimSpace(attr.AttributeValue)
+
+	var errs []string
+	switch n := utf8.RuneCountInString(attr.AttributeName); {
+	case n == 0:
+		errs = append(errs, "属性名称不能为空")
+	case n > 100:
+		errs = append(errs, "属性名称不能超过100个字符")
+	}
+	switch n := utf8.RuneCountInString(attr.AttributeValue); {
+	case n == 0:
+		errs = append(errs, "属性值不能为空")
+	case n > 255:
+		errs = append(errs, "属性值不能超过255个字符")
+	}
+	if math.IsNaN(attr.PriceFactor) || attr.PriceFactor <= 0 || attr.PriceFactor >= maxFactor {
+		errs = append(errs, "估价倍数必须大于0且小于100000000")
+	}
+	if math.IsNaN(attr.GradeFactor) || math.Abs(attr.GradeFactor) >= maxFactor {
+		errs = append(errs, "等级增量的绝对值必须小于100000000")
+	}
+
+	kind := attributeKind(attr.AttributeName)
+	switch attr.AttributeType {
+	case model.AttributeTypeBase:
+		if kind == "" {
+			errs = append(errs, "基础属性的名称必须以“后缀”结尾或包含“长度”、“结构”、“形态”，否则不会参与估价")
+		}
+	case model.AttributeTypeOther:
+	default:
+		errs = append(errs, fmt.Sprintf("属性类型必须是%s", strings.Join(AttributeTypes, "或")))
+	}
+
+	switch kind {
+	case "tld":
+		if strings.HasPrefix(attr.AttributeValue, ".") {
+			errs = append(errs, "后缀属性的值不需要以 . 开头，如 com")
+		}
+	case "pattern":
+		if !pattern.Known(attr.AttributeValue) && !pinyin.Known(attr.AttributeValue) {
+			tags := append(append([]string{}, pattern.Tags...), pinyin.Tags...)
+			errs = append(errs, fmt.Sprintf("形态属性的值必须是%s之一", strings.Join(tags, "、")))
+		}
+	case "length":
+		n, err := strconv.Atoi(attr.AttributeValue)
+		if err != nil || n <= 0 {
+			errs = append(errs, "长度属性的值必须是正整数")
+			break
+		}
+		if attr.HasRange() && !attr.InRange(float64(n)) {
+			errs = append(errs, "长度属性的值必须在取值范围内，如 9位及以上长度 的值为 9、下限为 9")
+		}
+	}
+
+	if attr.HasRange() {
+		if kind != "length" || attr.AttributeType != model.AttributeTypeBase {
+			errs = append(errs, "只有长度属性可以设置取值范围")
+		}
+		for _, bound := range []*float64{attr.MinValue, attr.MaxValue} {
+			if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+				errs = append(errs, "取值范围的上下限必须是有限的数值")
+			}
+		}
+		if attr.MinValue != nil && attr.MaxValue != nil && *attr.MinValue >= *attr.MaxValue {
+			errs = append(errs, fmt.Sprintf("取值范围的下限 %g 必须小于上限 %g", *attr.MinValue, *attr.MaxValue))
+		}
+	}
+	return errs
+}
+
+// duplicateAttribute 检查 others 中是否已有相同后缀、长度、结构或形态的基础属性，有则返回说明
+// 长度属性的取值范围不能重叠，否则同一长度会匹配多个属性
+func duplicateAttribute(attr *model.DomainAttribute, others []model.DomainAttribute) string {
+	for _, other := range others {
+		if !attributeConflict(attr, &other) {
+			continue
+		}
+		if other.AttributeValue == attr.AttributeValue {
+			return fmt.Sprintf("属性值 %s 已由属性“%s”(ID %d) 使用", attr.AttributeValue, other.AttributeName, other.ID)
+		}
+		return fmt.Sprintf("长度范围 %s 与属性“%s”(ID %d) 的长度范围 %s 重叠",
+			formatRange(lengthRange(attr)), other.AttributeName, other.ID, formatRange(lengthRange(&other)))
+	}
+	return ""
+}
+
+// attributeConflict 判断两个基础属性是否用于同一后缀、长度、结构或形态，长度属性按取值范围判断
+func attributeConflict(a, b *model.DomainAttribute) bool {
+	kind := attributeKind(a.AttributeName)
+	if kind == "" || a.AttributeType != model.AttributeTypeBase ||
+		b.AttributeType != model.AttributeTypeBase || attributeKind(b.AttributeName) != kind {
+		return false
+	}
+	if kind != "length" {
+		return a.AttributeValue == b.AttributeValue
+	}
+	aMin, aMax := lengthRange(a)
+	bMin, bMax := lengthRange(b)
+	return aMin < bMax && bMin < aMax
+}
+
+// lengthRange 返回长度属性匹配的长度范围 [min, max)，没有设置取值范围时只匹配属性值
+func lengthRange(attr *model.DomainAttribute) (float64, float64) {
+	if !attr.HasRange() {
+		n, _ := strconv.Atoi(attr.AttributeValue)
+		return float64(n), float64(n + 1)
+	}
+	min, max := math.Inf(-1), math.Inf(1)
+	if attr.MinValue != nil {
+		min = *attr.MinValue
+	}
+	if attr.MaxValue != nil {
+		max = *attr.MaxValue
+	}
+	return min, max
+}
+
+// formatRange 将取值范围格式化为 [min, max)，不限的一端显示为 ∞
+func formatRange(min, max float64) string {
+	bound := func(v float64) string {
+		switch {
+		case math.IsInf(v, 1):
+			return "∞"
+		case math.IsInf(v, -1):
+			return "-∞"
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("[%s, %s)", bound(min), bound(max))
+}
+
+// attributeKind 按名称约定返回基础属性的用途：tld、length、structure 或 pattern
+func attributeKind(name string) string {
+	switch {
+	case strings.HasSuffix(name, "后缀"):
+		return "tld"
+	case strings.Contains(name, "长度"):
+		return "length"
+	case strings.Contains(name, "结构"):
+		return "structure"
+	case strings.Contains(name, "形态"):
+		return "pattern"
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+)
+
+// MaxReasonLength 是变更原因的最大长度，与 audit_logs.reason 列一致
+const MaxReasonLength = 500
+
+// 审计日志查询的默认和最大条数
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// Change 描述一次规则变更的操作者、来源和原因，随修改操作传入并记录到审计日志
+type Change struct {
+	Actor  string // 操作者，如管理员用户名
+	Source string // 变更来源，如 model.AuditSourceAdmin
+	Reason string // 变更原因，可以为空
+}
+
+// author 返回记录为规则集版本创建者的名称，如 admin:alice
+func (c Change) author() string {
+	if c.Actor == "" {
+		return c.Source
+	}
+	return c.Source + ":" + c.Actor
+}
+
+// AuditService 记录和查询规则与配置的变更
+type AuditService struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService 创建一个新的AuditService实例
+func NewAuditService(repo repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// List 按条件查询审计日志，未指定条数时返回最近的100条
+func (s *AuditService) List(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return s.repo.ListAuditLogs(ctx, filter)
+}
+
+// record 保存一条审计日志，before 和 after 为 nil 时对应的值为空
+// 变更已经生效，因此写入不随请求取消，失败时只记录日志，不影响修改操作的结果
+func (s *AuditService) record(change Change, entityType string, entityID int64, entityName, action string, before, after interface{}) {
+	entry := &model.AuditLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		EntityName: entityName,
+		Action:     action,
+		Actor:      change.Actor,
+		Source:     change.Source,
+		Reason:     change.Reason,
+		CreatedAt:  time.Now(),
+	}
+
+	var err error
+	if entry.Before, err = marshalAuditValue(before); err == nil {
+		if entry.After, err = marshalAuditValue(after); err == nil {
+			err = s.repo.SaveAuditLog(context.Background(), entry)
+		}
+	}
+	if err != nil {
+		log.Printf("记录 %s %d 的审计日志失败: %v", entityType, entityID, err)
+	}
+}
+
+// marshalAuditValue 将变更前后的值序列化为JSON，nil 返回空值
+func marshalAuditValue(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"domainweb/internal/brand"
+	"domainweb/internal/dict"
+)
+
+// BrandabilityProvider 按读音特征为纯字母的域名主体计算可品牌化评分，不访问网络
+type BrandabilityProvider struct {
+	scorer *brand.Scorer
+}
+
+// NewBrandabilityProvider 用单词表训练评分模型，创建一个新的BrandabilityProvider实例
+func NewBrandabilityProvider(d *dict.Dictionary) *BrandabilityProvider {
+	words := d.Words()
+	corpus := make([]string, len(words))
+	for i, w := range words {
+		corpus[i] = w.Text
+	}
+	return &BrandabilityProvider{scorer: brand.NewScorer(corpus)}
+}
+
+// Name 返回数据源名称
+func (p *BrandabilityProvider) Name() string { return "brandability" }
+
+// Keys 返回数据源产出的属性键
+func (p *BrandabilityProvider) Keys() []string {
+	return []string{"brandability"}
+}
+
+// Fetch 计算域名主体的可品牌化评分（0到100），主体不是纯字母时不产出属性
+func (p *BrandabilityProvider) Fetch(ctx context.Context, domain string) (map[string]interface{}, error) {
+	label, _, _ := strings.Cut(domain, ".")
+	score, ok := p.scorer.Score(label)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return map[string]interface{}{"brandability": score}, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"domainweb/internal/bundle"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+)
+
+// ErrInvalidBundle 表示规则包未通过校验
+var ErrInvalidBundle = errors.New("规则包无效")
+
+// ImportResult 表示导入规则包的结果，DryRun 为 true 时只比较差异，没有修改数据库
+type ImportResult struct {
+	DryRun         bool           `json:"dryRun"`
+	Attributes     bundle.Summary `json:"attributes"`     // 域名属性的变更数量
+	ValuationRules bundle.Summary `json:"valuationRules"` // 估价规则的变更数量
+	Diff           *bundle.Diff   `json:"diff"`
+	RuleSetVersion int64          `json:"ruleSetVersion,omitempty"` // 导入后的规则集版本
+}
+
+// BundleService 导出和导入规则包
+// 导入时规则包中的内容将完全取代当前的域名属性和估价规则：新增缺少的记录、修改不同的记录并删除规则包中没有的记录
+type BundleService struct {
+	repo     repository.DomainRepository
+	rules    *RuleService
+	ruleSets *RuleSetService
+	audit    *AuditService
+}
+
+// NewBundleService 创建一个新的BundleService实例
+func NewBundleService(repo repository.DomainRepository, rules *RuleService, ruleSets *RuleSetService, audit *AuditService) *BundleService {
+	return &BundleService{repo: repo, rules: rules, ruleSets: ruleSets, audit: audit}
+}
+
+// Export 将当前规则集版本中的域名属性和估价规则导出为规则包
+func (s *BundleService) Export(ctx context.Context) (*bundle.Bundle, error) {
+	ruleSet, err := s.ruleSets.Current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取规则集失败: %w", err)
+	}
+
+	b := bundle.New(ruleSet.Content.Attributes, ruleSet.Content.ValuationRules)
+	b.RuleSetVersion = ruleSet.Version
+	return b, nil
+}
+
+// Import 校验规则包并与当前数据库比较，dryRun 为 false 时在一个事务中应用差异，
+// 并为每项变更记录审计日志、创建新的规则集版本
+func (s *BundleService) Import(ctx context.Context, b *bundle.Bundle, change Change, dryRun bool) (*ImportResult, error) {
+	currentAttrs, err := s.repo.GetDomainAttributes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取域名属性失败: %w", err)
+	}
+	currentRules, err := s.repo.GetValuationRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取估价规则失败: %w", err)
+	}
+
+	attrs, rules, err := s.validate(b, currentAttrs)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := bundle.Compare(currentAttrs, currentRules, attrs, rules)
+	result := &ImportResult{
+		DryRun:         dryRun,
+		Attributes:     diff.AttributeSummary(len(attrs)),
+		ValuationRules: diff.RuleSummary(len(rules)),
+		Diff:           diff,
+	}
+	if dryRun || diff.Empty() {
+		return result, nil
+	}
+
+	s.ruleSets.checkpoint(ctx)
+	changes := diff.Changes()
+	if err := s.repo.ApplyRuleChanges(ctx, changes); err != nil {
+		return nil, fmt.Errorf("导入规则包失败: %w", err)
+	}
+	s.recordChanges(change, diff, changes)
+
+	comment := fmt.Sprintf("导入规则包：属性新增 %d、修改 %d、删除 %d，规则新增 %d、修改 %d、删除 %d",
+		result.Attributes.Created, result.Attributes.Updated, result.Attributes.Deleted,
+		result.ValuationRules.Created, result.ValuationRules.Updated, result.ValuationRules.Deleted)
+	// 变更已经提交，创建版本不随请求取消
+	if ruleSet, err := s.ruleSets.Commit(context.Background(), change, comment); err != nil {
+		log.Printf("创建规则集版本失败: %v", err)
+	} else {
+		result.RuleSetVersion = ruleSet.Version
+	}
+	return result, nil
+}
+
+// validate 校验规则包中的所有属性和规则，返回规范化后的内容，错误一次全部列出
+// 与 current 中的记录完全相同的属性不检查取值，以便早期录入、不符合现行校验的属性可以原样导出再导入
+func (s *BundleService) validate(b *bundle.Bundle, current []model.DomainAttribute) ([]model.DomainAttribute, []model.ValuationRule, error) {
+	var errs []string
+
+	attrs := b.DomainAttributes()
+	for i := range attrs {
+		attr := &attrs[i]
+		if problems := attributeProblems(attr); len(problems) > 0 && !containsAttribute(current, *attr) {
+			errs = append(errs, fmt.Sprintf("第 %d 个属性“%s”: %s", i+1, attr.AttributeName, strings.Join(problems, "、")))
+			continue
+		}
+		// 同一后缀、长度、结构或形态只能有一个基础属性，长度属性的取值范围不能重叠
+		for j := 0; j < i; j++ {
+			if !attributeConflict(attr, &attrs[j]) {
+				continue
+			}
+			if attr.AttributeValue == attrs[j].AttributeValue {
+				errs = append(errs, fmt.Sprintf("第 %d 个属性“%s”: 与第 %d 个属性的属性值 %s 重复", i+1, attr.AttributeName, j+1, attr.AttributeValue))
+			} else {
+				errs = append(errs, fmt.Sprintf("第 %d 个属性“%s”: 长度范围 %s 与第 %d 个属性的长度范围 %s 重叠",
+					i+1, attr.AttributeName, formatRange(lengthRange(attr)), j+1, formatRange(lengthRange(&attrs[j]))))
+			}
+			break
+		}
+	}
+
+	rules := b.Rules()
+	for i, rule := range rules {
+		if err := s.rules.Validate(rule); err != nil {
+			errs = append(errs, fmt.Sprintf("第 %d 条规则“%s”: %v", i+1, rule.Label, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidBundle, strings.Join(errs, "; "))
+	}
+	return attrs, rules, nil
+}
+
+// containsAttribute 判断 attrs 中是否有除ID外与 attr 完全相同的属性
+func containsAttribute(attrs []model.DomainAttribute, attr model.DomainAttribute) bool {
+	for _, other := range attrs {
+		other.ID = attr.ID
+		if other.Equal(attr) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordChanges 为导入的每项变更记录审计日志，新增记录的ID取自应用后的 changes
+func (s *BundleService) recordChanges(change Change, diff *bundle.Diff, changes *model.RuleChanges) {
+	created := 0
+	for _, c := range diff.Attributes {
+		switch c.Action {
+		case bundle.ActionCreate:
+			attr := changes.CreateAttributes[created]
+			created++
+			s.audit.record(change, model.AuditEntityAttribute, attr.ID, attr.AttributeName, c.Action, nil, attr)
+		case bundle.ActionUpdate:
+			s.audit.record(change, model.AuditEntityAttribute, c.After.ID, c.After.AttributeName, c.Action, c.Before, c.After)
+		case bundle.ActionDelete:
+			s.audit.record(change, model.AuditEntityAttribute, c.Before.ID, c.Before.AttributeName, c.Action, c.Before, nil)
+		}
+	}
+
+	created = 0
+	for _, c := range diff.ValuationRules {
+		switch c.Action {
+		case bundle.ActionCreate:
+			rule := changes.CreateRules[created]
+			created++
+			s.audit.record(change, model.AuditEntityRule, rule.ID, rule.AttributeKey, c.Action, nil, rule)
+		case bundle.ActionUpdate:
+			s.audit.record(change, model.AuditEntityRule, c.After.ID, c.After.AttributeKey, c.Action, c.Before, c.After)
+		case bundle.ActionDelete:
+			s.audit.record(change, model.AuditEntityRule, c.Before.ID, c.Before.AttributeKey, c.Action, c.Before, nil)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"domainweb/internal/bundle"
+	"domainweb/internal/config"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+// newTestBundleService 使用已迁移的数据库创建规则包服务
+func newTestBundleService(t *testing.T) (*BundleService, repository.DomainRepository, *RuleSetService, *AuditService) {
+	t.Helper()
+	db := testutil.OpenMigratedSQLite(t)
+
+	cfg := config.Default()
+	cfg.Dynamic.MockMode = config.MockModeDeterministic
+	providers, err := NewDefaultProviderRegistry(cfg.Dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewDomainRepository(db, repository.SQLite)
+	audit := NewAuditService(repository.NewAuditRepository(db))
+	catalog := NewAttributeCatalog(repo, cfg.Estimation.AttributeRefreshDuration())
+	ruleSets := NewRuleSetService(repository.NewRuleSetRepository(db), catalog, audit, cfg.Estimation)
+	rules := NewRuleService(repo, providers, ruleSets, audit)
+	return NewBundleService(repo, rules, ruleSets, audit), repo, ruleSets, audit
+}
+
+func TestBundleImport(t *testing.T) {
+	ctx := context.Background()
+	s, repo, ruleSets, audit := newTestBundleService(t)
+	change := Change{Actor: "alice", Source: model.AuditSourceCLI}
+
+	b, err := s.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	before, err := repo.GetDomainAttributes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := ruleSets.List(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs, err := audit.List(ctx, model.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 导入导出的内容没有差异
+	result, err := s.Import(ctx, b, change, false)
+	if err != nil || !result.Diff.Empty() || result.RuleSetVersion != 0 {
+		t.Fatalf("Import(未修改) = %+v, %v", result, err)
+	}
+
+	// 修改一个属性、删除一条规则、新增一条规则
+	b.Attributes[0].PriceFactor += 1
+	removed := b.ValuationRules[0]
+	b.ValuationRules = append(b.ValuationRules[1:], bundle.Rule{
+		AttributeKey: "alexa_rank", Operator: "<", Value: "10", PriceFactor: 5, Label: "排名极高", Priority: 1,
+	})
+
+	result, err = s.Import(ctx, b, change, true)
+	if err != nil {
+		t.Fatalf("Import(dryRun) error = %v", err)
+	}
+	if !result.DryRun || result.Attributes.Updated != 1 ||
+		result.ValuationRules.Created != 1 || result.ValuationRules.Deleted != 1 {
+		t.Errorf("Import(dryRun) = %+v / %+v", result.Attributes, result.ValuationRules)
+	}
+
+	// 试运行不修改数据库、不记录审计日志、不创建规则集版本
+	after, _ := repo.GetDomainAttributes(ctx)
+	if after[0].PriceFactor != before[0].PriceFactor {
+		t.Error("试运行修改了域名属性")
+	}
+	if list, _ := audit.List(ctx, model.AuditFilter{}); len(list) != len(logs) {
+		t.Errorf("试运行记录了 %d 条审计日志", len(list)-len(logs))
+	}
+	if list, _ := ruleSets.List(ctx, 100); len(list) != len(versions) {
+		t.Errorf("试运行创建了规则集版本: %d -> %d", len(versions), len(list))
+	}
+
+	result, err = s.Import(ctx, b, change, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.DryRun || result.RuleSetVersion == 0 {
+		t.Errorf("Import() = %+v", result)
+	}
+	after, _ = repo.GetDomainAttributes(ctx)
+	if after[0].PriceFactor != before[0].PriceFactor+1 {
+		t.Errorf("导入后的估价倍数 = %v, want %v", after[0].PriceFactor, before[0].PriceFactor+1)
+	}
+	rules, _ := repo.GetValuationRules(ctx)
+	for _, rule := range rules {
+		if rule.Label == removed.Label && rule.AttributeKey == removed.AttributeKey && rule.Value == removed.Value {
+			t.Errorf("规则包中没有的规则 %s 没有被删除", removed.Label)
+		}
+	}
+	// 每项变更记录一条审计日志
+	for entity, want := range map[string]int{model.AuditEntityAttribute: 1, model.AuditEntityRule: 2} {
+		list, _ := audit.List(ctx, model.AuditFilter{EntityType: entity})
+		if len(list) != want {
+			t.Errorf("导入记录了 %d 条 %s 审计日志，want %d", len(list), entity, want)
+		}
+	}
+}
+
+func TestBundleImportInvalid(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, _ := newTestBundleService(t)
+
+	b, err := s.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Attributes = append(b.Attributes, b.Attributes[0])
+	b.ValuationRules = append(b.ValuationRules,
+		bundle.Rule{AttributeKey: "alexa_rank", Operator: "~", PriceFactor: 1, Label: "未知运算符"},
+		bundle.Rule{AttributeKey: "alexa_rank", Operator: "exists", Label: "零倍数"},
+	)
+	before, _ := repo.GetValuationRules(ctx)
+
+	for _, dryRun := range []bool{true, false} {
+		_, err = s.Import(ctx, b, Change{Source: model.AuditSourceCLI}, dryRun)
+		if !errors.Is(err, ErrInvalidBundle) {
+			t.Fatalf("Import(dryRun=%v) error = %v, want ErrInvalidBundle", dryRun, err)
+		}
+		// 所有错误一次列出
+		for _, want := range []string{"重复", "不支持的运算符", "估价倍数必须大于0"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Import() error = %v, want error containing %q", err, want)
+			}
+		}
+	}
+	if after, _ := repo.GetValuationRules(ctx); len(after) != len(before) {
+		t.Errorf("无效的规则包修改了估价规则: %d -> %d", len(before), len(after))
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"domainweb/internal/dict"
+)
+
+// DictionaryProvider 按内置的英文单词表切分域名主体，不访问网络
+type DictionaryProvider struct {
+	dict *dict.Dictionary
+}
+
+// NewDictionaryProvider 创建一个新的DictionaryProvider实例
+func NewDictionaryProvider(d *dict.Dictionary) *DictionaryProvider {
+	return &DictionaryProvider{dict: d}
+}
+
+// Name 返回数据源名称
+func (p *DictionaryProvider) Name() string { return "dictionary" }
+
+// Keys 返回数据源产出的属性键
+func (p *DictionaryProvider) Keys() []string {
+	return []string{"word_count", "dict_hit", "word_rank", "word_pos", "words"}
+}
+
+// Fetch 将域名主体切分为英文单词
+// word_count 为单词数量，无法完整切分时为0；dict_hit 表示主体本身就是一个单词；
+// word_rank 为其中最不常用单词的词频排名；word_pos 为最后一个单词（通常是中心词）的词性；words 为以空格分隔的单词
+func (p *DictionaryProvider) Fetch(ctx context.Context, domain string) (map[string]interface{}, error) {
+	label, _, _ := strings.Cut(domain, ".")
+	words := p.dict.Segment(label)
+	if len(words) == 0 {
+		return map[string]interface{}{"word_count": 0, "dict_hit": false}, nil
+	}
+
+	texts := make([]string, len(words))
+	rank := 0
+	for i, w := range words {
+		texts[i] = w.Text
+		if w.Rank > rank {
+			rank = w.Rank
+		}
+	}
+	return map[string]interface{}{
+		"word_count": len(words),
+		"dict_hit":   len(words) == 1,
+		"word_rank":  rank,
+		"word_pos":   words[len(words)-1].POS,
+		"words":      strings.Join(texts, " "),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"domainweb/internal/dict"
+)
+
+func TestDictionaryProvider(t *testing.T) {
+	d, err := dict.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewDictionaryProvider(d)
+	shop, _ := d.Lookup("shop")
+	cloud, _ := d.Lookup("cloud")
+
+	tests := []struct {
+		domain string
+		want   map[string]interface{}
+	}{
+		{"cloudshop.com", map[string]interface{}{
+			"word_count": 2, "dict_hit": false, "word_rank": cloud.Rank, "word_pos": dict.Noun, "words": "cloud shop",
+		}},
+		{"shop.com.cn", map[string]interface{}{
+			"word_count": 1, "dict_hit": true, "word_rank": shop.Rank, "word_pos": dict.Noun, "words": "shop",
+		}},
+		{"zzqx.com", map[string]interface{}{"word_count": 0, "dict_hit": false}},
+	}
+	for _, tt := range tests {
+		got, err := p.Fetch(context.Background(), tt.domain)
+		if err != nil {
+			t.Fatalf("Fetch(%q) error = %v", tt.domain, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Fetch(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+
+	// 产出的属性键都在 Keys 中声明
+	got, _ := p.Fetch(context.Background(), "cloudshop.com")
+	declared := map[string]bool{}
+	for _, key := range p.Keys() {
+		declared[key] = true
+	}
+	if len(got) != len(declared) {
+		t.Errorf("Fetch() 产出 %d 个属性，Keys() 声明 %d 个", len(got), len(declared))
+	}
+	for key := range got {
+		if !declared[key] {
+			t.Errorf("属性 %s 没有在 Keys() 中声明", key)
+		}
+	}
+}
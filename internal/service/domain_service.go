@@ -1,339 +1,421 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"domainweb/internal/config"
+	"domainweb/internal/dict"
 	"domainweb/internal/model"
-	"domainweb/internal/repository"
+	"domainweb/internal/pattern"
+	"domainweb/internal/pinyin"
+	"domainweb/internal/psl"
+	"domainweb/internal/rules"
+
+	"golang.org/x/net/idna"
 )
 
 // DomainService 处理域名估价的业务逻辑
 type DomainService struct {
-	repo               *repository.DomainRepository
+	ruleSets           *RuleSetService
 	dynamicAttrService *DynamicAttributeService
+	suffixes           *psl.List        // 公共后缀列表，用于拆分有效顶级域名
+	words              *dict.Dictionary // 英文词典，由单个英文单词组成的主体不标记拼音形态
+	batchConcurrency   int              // 批量估价的并发数
+	batchMaxSize       int              // 单次批量估价的最大域名数量
+	now                func() time.Time // 默认注册日期和规则中注册年数、到期天数的参照时钟
+
+	snapshotMu sync.Mutex
+	snapshot   *ruleSnapshot // 当前规则集版本的快照，版本未变化时复用
 }
 
-// NewDomainService 创建一个新的DomainService实例
-func NewDomainService(repo *repository.DomainRepository) *DomainService {
+// NewDomainService 创建一个新的DomainService实例，估价基数由规则集提供
+func NewDomainService(ruleSets *RuleSetService, dynamicAttrService *DynamicAttributeService, cfg config.EstimationConfig, suffixes *psl.List, words *dict.Dictionary) *DomainService {
 	return &DomainService{
-		repo:               repo,
-		dynamicAttrService: NewDynamicAttributeService(),
+		ruleSets:           ruleSets,
+		dynamicAttrService: dynamicAttrService,
+		suffixes:           suffixes,
+		words:              words,
+		batchConcurrency:   cfg.BatchConcurrency,
+		batchMaxSize:       cfg.BatchMaxSize,
+		now:                time.Now,
 	}
 }
 
-// EstimateDomain 估算域名价值和品相等级
-func (s *DomainService) EstimateDomain(domainName string) (*model.EstimationResult, error) {
-	// 基础价格和等级
-	const basePrice = 25.0
-	const baseGrade = -0.5
+// SetClock 设置估价的参照时钟，确定性模式下使用固定时钟使估价结果可复现
+func (s *DomainService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// ruleSnapshot 是一次估价使用的属性规则快照，创建后不再修改，可以被并发的估价共享
+// 基础属性按后缀、长度和结构建立索引，估价时直接查找而不必遍历所有属性
+type ruleSnapshot struct {
+	version             int64   // 规则集版本，预览未保存的修改时为0
+	basePrice           float64 // 估价基数
+	baseGrade           float64 // 等级基数
+	otherAttributes     []model.DomainAttribute
+	tldAttributes       map[string]model.DomainAttribute // 后缀 -> 属性
+	lengthAttributes    map[string]model.DomainAttribute // 长度 -> 属性，如 "4"，只包含没有设置取值范围的属性
+	lengthRanges        []model.DomainAttribute          // 设置了取值范围的长度属性，如 9位及以上长度
+	structureAttributes map[string]model.DomainAttribute // 结构 -> 属性，如 "纯字母"
+	patternAttributes   map[string]model.DomainAttribute // 形态 -> 属性，如 "AABB"
+	engine              *rules.Engine                    // 动态属性估价规则
+}
+
+// loadRules 返回当前规则集版本的估价快照，版本未变化时复用上次创建的快照
+func (s *DomainService) loadRules(ctx context.Context) (*ruleSnapshot, error) {
+	ruleSet, err := s.ruleSets.Current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取规则集失败: %w", err)
+	}
 
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+	if s.snapshot != nil && s.snapshot.version == ruleSet.Version {
+		return s.snapshot, nil
+	}
+	snapshot, err := s.newRuleSnapshot(ruleSet.Version, ruleSet.Content)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshot = snapshot
+	return snapshot, nil
+}
+
+// newRuleSnapshot 按属性类型和名称为规则集中的域名属性建立索引，并编译动态属性估价规则
+// 同一长度、结构或形态有多个基础属性时使用排在前面的一个
+func (s *DomainService) newRuleSnapshot(version int64, content *model.RuleSetContent) (*ruleSnapshot, error) {
+	snapshot := &ruleSnapshot{
+		version:             version,
+		basePrice:           content.BasePrice,
+		baseGrade:           content.BaseGrade,
+		tldAttributes:       make(map[string]model.DomainAttribute),
+		lengthAttributes:    make(map[string]model.DomainAttribute),
+		structureAttributes: make(map[string]model.DomainAttribute),
+		patternAttributes:   make(map[string]model.DomainAttribute),
+	}
+	for _, attr := range content.Attributes {
+		switch attr.AttributeType {
+		case model.AttributeTypeBase:
+			if strings.Contains(attr.AttributeName, "长度") {
+				if attr.HasRange() {
+					snapshot.lengthRanges = append(snapshot.lengthRanges, attr)
+				} else if _, ok := snapshot.lengthAttributes[attr.AttributeValue]; !ok {
+					snapshot.lengthAttributes[attr.AttributeValue] = attr
+				}
+			}
+			if strings.Contains(attr.AttributeName, "结构") {
+				if _, ok := snapshot.structureAttributes[attr.AttributeValue]; !ok {
+					snapshot.structureAttributes[attr.AttributeValue] = attr
+				}
+			}
+			if strings.Contains(attr.AttributeName, "形态") {
+				if _, ok := snapshot.patternAttributes[attr.AttributeValue]; !ok {
+					snapshot.patternAttributes[attr.AttributeValue] = attr
+				}
+			}
+		case model.AttributeTypeOther:
+			snapshot.otherAttributes = append(snapshot.otherAttributes, attr)
+		}
+		// TLD属性以“后缀”结尾，属性值为后缀，如 com
+		if strings.HasSuffix(attr.AttributeName, "后缀") {
+			snapshot.tldAttributes[attr.AttributeValue] = attr
+		}
+	}
+
+	var err error
+	snapshot.engine, err = rules.NewEngine(content.ValuationRules, s.dynamicAttrService.Registry().AllKeys())
+	if err != nil {
+		return nil, fmt.Errorf("规则集 %d 的估价规则无效: %w", version, err)
+	}
+	return snapshot, nil
+}
+
+// lengthAttribute 返回与长度匹配的长度属性：按属性值精确匹配的优先，其次是第一个取值范围包含该长度的属性
+func (s *ruleSnapshot) lengthAttribute(length int) (model.DomainAttribute, bool) {
+	if attr, ok := s.lengthAttributes[strconv.Itoa(length)]; ok {
+		return attr, true
+	}
+	for _, attr := range s.lengthRanges {
+		if attr.InRange(float64(length)) {
+			return attr, true
+		}
+	}
+	return model.DomainAttribute{}, false
+}
+
+// EstimateDomain 估算域名价值和品相等级，ctx 取消或超时时停止查询规则和获取动态属性并返回错误
+func (s *DomainService) EstimateDomain(ctx context.Context, domainName string) (*model.EstimationResult, error) {
+	snapshot, err := s.loadRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.estimate(ctx, domainName, snapshot)
+}
+
+// PreviewEstimate 分别按当前的域名属性和修改后的域名属性估价同一个域名，用于在保存修改前预览影响
+// modify 接收当前所有域名属性的副本，返回修改后的属性
+func (s *DomainService) PreviewEstimate(ctx context.Context, domainName string, modify func([]model.DomainAttribute) []model.DomainAttribute) (before, after *model.EstimationResult, err error) {
+	ruleSet, err := s.ruleSets.Current(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取规则集失败: %w", err)
+	}
+
+	current, err := s.newRuleSnapshot(ruleSet.Version, ruleSet.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	content := *ruleSet.Content
+	content.Attributes = modify(append([]model.DomainAttribute(nil), content.Attributes...))
+	modified, err := s.newRuleSnapshot(0, &content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if before, err = s.estimate(ctx, domainName, current); err != nil {
+		return nil, nil, err
+	}
+	if after, err = s.estimate(ctx, domainName, modified); err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// 重新估价历史记录时可选的规则集
+const (
+	RerunOriginal = "original" // 记录估价时使用的规则集版本
+	RerunCurrent  = "current"  // 当前生效的规则
+	RerunBoth     = "both"     // 两者都估价，便于对比
+)
+
+// Rerun 重新估价一条历史记录，against 指定使用记录的原始规则集版本、当前规则或两者
+// 原始版本使用记录保存的动态属性和参照时间，从而复现记录的估价；没有保存输入的旧记录按当前数据重新获取。
+// 当前规则按当前数据重新获取动态属性；重新估价的结果不保存到历史记录
+func (s *DomainService) Rerun(ctx context.Context, record *model.HistoryRecord, against string) (*model.RerunResult, error) {
+	result := &model.RerunResult{Record: *record}
+
+	if against == RerunOriginal || against == RerunBoth {
+		if record.RuleSetVersion == 0 {
+			if against == RerunOriginal {
+				return nil, fmt.Errorf("%w: 记录 %d 早于规则集版本化，没有原始规则集", ErrRuleSetNotFound, record.ID)
+			}
+		} else {
+			ruleSet, err := s.ruleSets.Get(ctx, record.RuleSetVersion)
+			if err != nil {
+				return nil, err
+			}
+			snapshot, err := s.newRuleSnapshot(ruleSet.Version, ruleSet.Content)
+			if err != nil {
+				return nil, err
+			}
+			if record.Inputs != nil {
+				result.Original, err = s.estimateWithInputs(record.Domain, snapshot, record.Inputs)
+			} else {
+				result.Original, err = s.estimate(ctx, record.Domain, snapshot)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if against == RerunCurrent || against == RerunBoth {
+		snapshot, err := s.loadRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if result.Current, err = s.estimate(ctx, record.Domain, snapshot); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// BatchMaxSize 返回单次批量估价允许的最大域名数量
+func (s *DomainService) BatchMaxSize() int {
+	return s.batchMaxSize
+}
+
+// EstimateDomains 使用固定数量的工作协程批量估价，结果顺序与输入一致
+// 单个域名估价失败时记录在对应结果中，不影响其他域名；ctx 取消后未开始的域名直接返回取消错误
+func (s *DomainService) EstimateDomains(ctx context.Context, domainNames []string) ([]model.BatchEstimationItem, error) {
+	snapshot, err := s.loadRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.BatchEstimationItem, len(domainNames))
+	workers := s.batchConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(domainNames) {
+		workers = len(domainNames)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				items[i].Domain = domainNames[i]
+				if err := ctx.Err(); err != nil {
+					items[i].Error = err.Error()
+					continue
+				}
+				result, err := s.estimate(ctx, domainNames[i], snapshot)
+				if err != nil {
+					items[i].Error = err.Error()
+					continue
+				}
+				items[i].Result = result
+			}
+		}()
+	}
+
+	for i := range domainNames {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return items, nil
+}
+
+// estimate 使用给定的属性规则快照估价单个域名
+// 动态属性按规范化后的域名（如 https://www.abc.com/x 对应 abc.com）获取一次，同时用于注册日期和估价规则
+func (s *DomainService) estimate(ctx context.Context, domainName string, snapshot *ruleSnapshot) (*model.EstimationResult, error) {
 	// 解析域名
-	domain, err := s.parseDomain(domainName)
+	now := s.now()
+	domain, err := s.parseDomain(domainName, now)
 	if err != nil {
 		return nil, fmt.Errorf("解析域名失败: %w", err)
 	}
 
-	// 获取基础属性
-	baseAttributes, err := s.repo.GetAttributesByType("基础属性")
+	// 调用动态属性服务获取实时数据，获取失败时记录错误但继续处理，请求取消或超时时停止估价
+	dynamicAttrs, err := s.dynamicAttrService.GetDynamicAttributes(ctx, domain.Name)
 	if err != nil {
-		return nil, fmt.Errorf("获取基础属性失败: %w", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("获取动态属性失败: %v", err)
 	}
 
-	// 获取其他属性
-	otherAttributes, err := s.repo.GetAttributesByType("其他属性")
+	return s.evaluate(domain, snapshot, &model.EstimationInputs{DynamicAttributes: dynamicAttrs, ReferenceDate: now}), nil
+}
+
+// estimateWithInputs 使用历史记录保存的动态属性和参照时间估价，不重新获取动态属性
+func (s *DomainService) estimateWithInputs(domainName string, snapshot *ruleSnapshot, inputs *model.EstimationInputs) (*model.EstimationResult, error) {
+	domain, err := s.parseDomain(domainName, inputs.ReferenceDate)
 	if err != nil {
-		return nil, fmt.Errorf("获取其他属性失败: %w", err)
+		return nil, fmt.Errorf("解析域名失败: %w", err)
 	}
+	return s.evaluate(domain, snapshot, inputs), nil
+}
+
+// evaluate 按属性规则快照和估价输入计算估价结果
+func (s *DomainService) evaluate(domain *model.Domain, snapshot *ruleSnapshot, inputs *model.EstimationInputs) *model.EstimationResult {
+	dynamicAttrs := inputs.DynamicAttributes
+	applyRegistrationDates(domain, dynamicAttrs)
 
 	// 计算基础属性的影响
 	var baseAttrDetails []model.AttributeDetail
 	totalPriceFactor := 1.0
 	totalGradeFactor := 0.0
 
-	// 处理TLD属性
-	tldAttrs, err := s.repo.GetTLDAttributes()
-	if err != nil {
-		return nil, fmt.Errorf("获取TLD属性失败: %w", err)
+	// 处理TLD属性，国际化后缀的属性值可能以Unicode形式（如 中国）录入，展示时也使用Unicode形式
+	unicodeTLD := unicodeSuffix(domain)
+	tldAttr, ok := snapshot.tldAttributes[domain.TLD]
+	if !ok {
+		tldAttr, ok = snapshot.tldAttributes[unicodeTLD]
 	}
-
-	if tldAttr, ok := tldAttrs[domain.TLD]; ok {
+	if ok {
 		totalPriceFactor *= tldAttr.PriceFactor
 		totalGradeFactor += tldAttr.GradeFactor
 		baseAttrDetails = append(baseAttrDetails, model.AttributeDetail{
 			Name:        tldAttr.AttributeName,
-			Value:       domain.TLD,
-			Description: fmt.Sprintf("%s后缀", domain.TLD),
+			Value:       unicodeTLD,
+			Description: fmt.Sprintf("%s后缀", unicodeTLD),
 			PriceFactor: tldAttr.PriceFactor,
 			GradeFactor: tldAttr.GradeFactor,
 		})
 	}
 
 	// 处理长度属性
-	for _, attr := range baseAttributes {
-		if strings.Contains(attr.AttributeName, "位长度") &&
-			fmt.Sprintf("%d", domain.Length) == attr.AttributeValue {
-			totalPriceFactor *= attr.PriceFactor
-			totalGradeFactor += attr.GradeFactor
-			baseAttrDetails = append(baseAttrDetails, model.AttributeDetail{
-				Name:        attr.AttributeName,
-				Value:       fmt.Sprintf("%d", domain.Length),
-				Description: fmt.Sprintf("%d位长度", domain.Length),
-				PriceFactor: attr.PriceFactor,
-				GradeFactor: attr.GradeFactor,
-			})
-			break
-		}
+	if attr, ok := snapshot.lengthAttribute(domain.Length); ok {
+		totalPriceFactor *= attr.PriceFactor
+		totalGradeFactor += attr.GradeFactor
+		baseAttrDetails = append(baseAttrDetails, model.AttributeDetail{
+			Name:        attr.AttributeName,
+			Value:       fmt.Sprintf("%d", domain.Length),
+			Description: fmt.Sprintf("%d位长度", domain.Length),
+			PriceFactor: attr.PriceFactor,
+			GradeFactor: attr.GradeFactor,
+		})
 	}
 
 	// 处理结构属性
-	for _, attr := range baseAttributes {
-		if strings.Contains(attr.AttributeName, "结构") &&
-			domain.Structure == attr.AttributeValue {
+	if attr, ok := snapshot.structureAttributes[domain.Structure]; ok {
+		totalPriceFactor *= attr.PriceFactor
+		totalGradeFactor += attr.GradeFactor
+		baseAttrDetails = append(baseAttrDetails, model.AttributeDetail{
+			Name:        attr.AttributeName,
+			Value:       domain.Structure,
+			Description: fmt.Sprintf("%s结构", domain.Structure),
+			PriceFactor: attr.PriceFactor,
+			GradeFactor: attr.GradeFactor,
+		})
+	}
+
+	// 处理形态属性，一个域名可以有多个形态，如 8888 同时是 AAAA 和 含8
+	for _, tag := range domain.Patterns {
+		if attr, ok := snapshot.patternAttributes[tag]; ok {
 			totalPriceFactor *= attr.PriceFactor
 			totalGradeFactor += attr.GradeFactor
 			baseAttrDetails = append(baseAttrDetails, model.AttributeDetail{
 				Name:        attr.AttributeName,
-				Value:       domain.Structure,
-				Description: fmt.Sprintf("%s结构", domain.Structure),
+				Value:       tag,
+				Description: fmt.Sprintf("%s形态", tag),
 				PriceFactor: attr.PriceFactor,
 				GradeFactor: attr.GradeFactor,
 			})
-			break
 		}
 	}
 
-	// 获取动态属性
-	var otherAttrDetails []model.AttributeDetail
-
-	// 调用动态属性服务获取实时数据
-	dynamicAttrs, err := s.dynamicAttrService.GetDynamicAttributes(domainName)
-	if err != nil {
-		// 如果获取动态属性失败，记录错误但继续处理
-		fmt.Printf("获取动态属性失败: %v\n", err)
-	}
-
 	// 处理动态属性
-	if dynamicAttrs != nil {
-		// 处理Alexa排名
-		if alexaRank, ok := dynamicAttrs["alexa_rank"].(int); ok {
-			var alexaAttr model.DomainAttribute
-
-			// 根据Alexa排名设置不同的影响因子
-			if alexaRank < 10000 {
-				alexaAttr = model.DomainAttribute{
-					AttributeName:  "Alexa排名优秀",
-					AttributeType:  "其他属性",
-					PriceFactor:    2.5,
-					GradeFactor:    0.8,
-					AttributeValue: strconv.Itoa(alexaRank),
-				}
-			} else if alexaRank < 100000 {
-				alexaAttr = model.DomainAttribute{
-					AttributeName:  "Alexa排名良好",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.8,
-					GradeFactor:    0.5,
-					AttributeValue: strconv.Itoa(alexaRank),
-				}
-			} else if alexaRank < 1000000 {
-				alexaAttr = model.DomainAttribute{
-					AttributeName:  "Alexa排名一般",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.2,
-					GradeFactor:    0.2,
-					AttributeValue: strconv.Itoa(alexaRank),
-				}
-			} else {
-				alexaAttr = model.DomainAttribute{
-					AttributeName:  "Alexa排名",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.0,
-					GradeFactor:    0.0,
-					AttributeValue: strconv.Itoa(alexaRank),
-				}
-			}
-
-			totalPriceFactor *= alexaAttr.PriceFactor
-			totalGradeFactor += alexaAttr.GradeFactor
-			otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
-				Name:        alexaAttr.AttributeName,
-				Value:       alexaAttr.AttributeValue,
-				Description: fmt.Sprintf("Alexa 排名 %s", alexaAttr.AttributeValue),
-				PriceFactor: alexaAttr.PriceFactor,
-				GradeFactor: alexaAttr.GradeFactor,
-			})
-		}
-
-		// 处理搜索量
-		if searchVolume, ok := dynamicAttrs["search_volume"].(int); ok {
-			var searchAttr model.DomainAttribute
-
-			// 根据搜索量设置不同的影响因子
-			if searchVolume > 10000 {
-				searchAttr = model.DomainAttribute{
-					AttributeName:  "搜索量巨大",
-					AttributeType:  "其他属性",
-					PriceFactor:    3.0,
-					GradeFactor:    0.9,
-					AttributeValue: strconv.Itoa(searchVolume),
-				}
-			} else if searchVolume > 5000 {
-				searchAttr = model.DomainAttribute{
-					AttributeName:  "搜索量很高",
-					AttributeType:  "其他属性",
-					PriceFactor:    2.2,
-					GradeFactor:    0.7,
-					AttributeValue: strconv.Itoa(searchVolume),
-				}
-			} else if searchVolume > 1000 {
-				searchAttr = model.DomainAttribute{
-					AttributeName:  "搜索量较高",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.8,
-					GradeFactor:    0.6,
-					AttributeValue: strconv.Itoa(searchVolume),
-				}
-			} else {
-				searchAttr = model.DomainAttribute{
-					AttributeName:  "搜索量",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.0,
-					GradeFactor:    0.0,
-					AttributeValue: strconv.Itoa(searchVolume),
-				}
-			}
-
-			totalPriceFactor *= searchAttr.PriceFactor
-			totalGradeFactor += searchAttr.GradeFactor
-			otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
-				Name:        searchAttr.AttributeName,
-				Value:       searchAttr.AttributeValue,
-				Description: fmt.Sprintf("搜索量 %s", searchAttr.AttributeValue),
-				PriceFactor: searchAttr.PriceFactor,
-				GradeFactor: searchAttr.GradeFactor,
-			})
-		}
-
-		// 处理相关域名状态
-		for key, value := range dynamicAttrs {
-			if strings.HasPrefix(key, "related_domain_") {
-				tld := strings.TrimPrefix(key, "related_domain_")
-				status, ok := value.(string)
-				if !ok {
-					continue
-				}
-
-				var relatedAttr model.DomainAttribute
-				isRegistered := !strings.Contains(status, "未注册")
-
-				if isRegistered {
-					// 相关域名已注册，降低当前域名价值
-					relatedAttr = model.DomainAttribute{
-						AttributeName:  fmt.Sprintf("%s相关域名已注册", tld),
-						AttributeType:  "其他属性",
-						PriceFactor:    0.86,
-						GradeFactor:    -0.1,
-						AttributeValue: status,
-					}
-				} else {
-					// 相关域名未注册，进一步降低当前域名价值
-					relatedAttr = model.DomainAttribute{
-						AttributeName:  fmt.Sprintf("%s相关域名未注册", tld),
-						AttributeType:  "其他属性",
-						PriceFactor:    0.65,
-						GradeFactor:    -0.2,
-						AttributeValue: "未注册",
-					}
-				}
-
-				totalPriceFactor *= relatedAttr.PriceFactor
-				totalGradeFactor += relatedAttr.GradeFactor
-				otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
-					Name:        relatedAttr.AttributeName,
-					Value:       relatedAttr.AttributeValue,
-					Description: fmt.Sprintf("%s.%s %s", strings.Split(domainName, ".")[0], tld, relatedAttr.AttributeValue),
-					PriceFactor: relatedAttr.PriceFactor,
-					GradeFactor: relatedAttr.GradeFactor,
-				})
-			}
-		}
-
-		// 处理社交媒体和电商数据
-		if tiebaPosts, ok := dynamicAttrs["tieba_posts"].(int); ok && tiebaPosts > 0 {
-			var tiebaAttr model.DomainAttribute
-
-			if tiebaPosts > 10000 {
-				tiebaAttr = model.DomainAttribute{
-					AttributeName:  "贴吧数量巨大",
-					AttributeType:  "其他属性",
-					PriceFactor:    2.25,
-					GradeFactor:    0.6,
-					AttributeValue: strconv.Itoa(tiebaPosts),
-				}
-			} else {
-				tiebaAttr = model.DomainAttribute{
-					AttributeName:  "贴吧数量",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.5,
-					GradeFactor:    0.3,
-					AttributeValue: strconv.Itoa(tiebaPosts),
-				}
-			}
-
-			totalPriceFactor *= tiebaAttr.PriceFactor
-			totalGradeFactor += tiebaAttr.GradeFactor
-			otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
-				Name:        tiebaAttr.AttributeName,
-				Value:       tiebaAttr.AttributeValue,
-				Description: fmt.Sprintf("贴吧数量 %s", tiebaAttr.AttributeValue),
-				PriceFactor: tiebaAttr.PriceFactor,
-				GradeFactor: tiebaAttr.GradeFactor,
-			})
-		}
-
-		// 处理淘宝商品数量
-		if taobaoProducts, ok := dynamicAttrs["taobao_products"].(int); ok && taobaoProducts > 0 {
-			var taobaoAttr model.DomainAttribute
-
-			if taobaoProducts > 1000 {
-				taobaoAttr = model.DomainAttribute{
-					AttributeName:  "淘宝商品数量多",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.5,
-					GradeFactor:    0.3,
-					AttributeValue: strconv.Itoa(taobaoProducts),
-				}
-			} else {
-				taobaoAttr = model.DomainAttribute{
-					AttributeName:  "淘宝商品",
-					AttributeType:  "其他属性",
-					PriceFactor:    1.18,
-					GradeFactor:    0.1,
-					AttributeValue: strconv.Itoa(taobaoProducts),
-				}
-			}
+	var otherAttrDetails []model.AttributeDetail
 
-			totalPriceFactor *= taobaoAttr.PriceFactor
-			totalGradeFactor += taobaoAttr.GradeFactor
-			otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
-				Name:        taobaoAttr.AttributeName,
-				Value:       taobaoAttr.AttributeValue,
-				Description: fmt.Sprintf("淘宝商品 %s", taobaoAttr.AttributeValue),
-				PriceFactor: taobaoAttr.PriceFactor,
-				GradeFactor: taobaoAttr.GradeFactor,
-			})
-		}
+	// 按估价规则处理动态属性，如Alexa排名、搜索量、相关域名注册情况等
+	// 表达式规则只引用域名字段时，即使没有动态属性也可能匹配
+	for _, match := range snapshot.engine.Evaluate(domain, dynamicAttrs, inputs.ReferenceDate) {
+		totalPriceFactor *= match.Rule.PriceFactor
+		totalGradeFactor += match.Rule.GradeFactor
+		otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
+			Name:        match.Name,
+			Value:       match.Value,
+			Description: match.Description,
+			PriceFactor: match.Rule.PriceFactor,
+			GradeFactor: match.Rule.GradeFactor,
+		})
 	}
 
 	// 如果没有获取到动态属性，使用静态属性作为备选
 	if len(otherAttrDetails) == 0 {
-		for _, attr := range otherAttributes {
-			// 简单示例：为每个域名随机选择一些其他属性
-			if strings.Contains(strings.ToLower(domainName), strings.ToLower(attr.AttributeValue)) {
+		for _, attr := range snapshot.otherAttributes {
+			// 按规范化后的域名匹配，Unicode形式录入的属性值（如 中文）按Unicode形式匹配
+			value := strings.ToLower(attr.AttributeValue)
+			if strings.Contains(domain.Name, value) || strings.Contains(domain.UnicodeName, value) {
 				totalPriceFactor *= attr.PriceFactor
 				totalGradeFactor += attr.GradeFactor
 				otherAttrDetails = append(otherAttrDetails, model.AttributeDetail{
@@ -348,86 +430,132 @@ func (s *DomainService) EstimateDomain(domainName string) (*model.EstimationResu
 	}
 
 	// 计算最终价格和等级
-	finalPrice := basePrice * totalPriceFactor
-	finalGrade := baseGrade + totalGradeFactor
+	finalPrice := snapshot.basePrice * totalPriceFactor
+	finalGrade := snapshot.baseGrade + totalGradeFactor
 
 	// 创建估价结果
 	result := &model.EstimationResult{
-		Domain:          domainName,
+		Domain:          domain.Name,
 		Grade:           finalGrade,
 		Price:           finalPrice,
 		BaseAttributes:  baseAttrDetails,
 		OtherAttributes: otherAttrDetails,
-		EstimationDate:  time.Now(),
+		RuleSetVersion:  snapshot.version,
+		EstimationDate:  s.now(),
+		Inputs:          inputs,
 	}
 
-	return result, nil
+	return result
 }
 
-// parseDomain 解析域名，提取TLD、长度和结构等信息
-func (s *DomainService) parseDomain(domainName string) (*model.Domain, error) {
-	// 移除http://和https://前缀
-	domainName = strings.TrimPrefix(domainName, "http://")
-	domainName = strings.TrimPrefix(domainName, "https://")
+// hostName 从用户输入的网址中取出主机名，去掉协议、用户信息、端口、路径、查询和片段
+func hostName(input string) string {
+	if strings.Contains(input, "://") {
+		if u, err := url.Parse(input); err == nil && u.Host != "" {
+			return u.Hostname()
+		}
+		input = input[strings.Index(input, "://")+3:]
+	}
+	if idx := strings.IndexAny(input, "/?#"); idx != -1 {
+		input = input[:idx]
+	}
+	if idx := strings.LastIndex(input, "@"); idx != -1 {
+		input = input[idx+1:]
+	}
+	if idx := strings.LastIndex(input, ":"); idx != -1 {
+		input = input[:idx]
+	}
+	return input
+}
 
-	// 移除www.前缀
-	domainName = strings.TrimPrefix(domainName, "www.")
+// parseDomain 解析域名，提取TLD、长度和结构等信息，不获取动态属性
+// 同时接受Unicode形式（中文.com）和punycode形式（xn--fiq228c.com）的国际化域名
+// now 是默认注册和到期日期的参照时间
+func (s *DomainService) parseDomain(domainName string, now time.Time) (*model.Domain, error) {
+	domainName = hostName(strings.TrimSpace(domainName))
 
-	// 移除路径部分
-	if idx := strings.Index(domainName, "/"); idx != -1 {
-		domainName = domainName[:idx]
+	// 规范化为小写punycode形式，同时完成全角句号、大小写等映射
+	asciiName, err := idna.Lookup.ToASCII(strings.TrimSuffix(domainName, "."))
+	if err != nil {
+		return nil, fmt.Errorf("无效的域名: %s: %w", domainName, err)
 	}
 
-	// 分割域名和TLD
-	parts := strings.Split(domainName, ".")
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("无效的域名格式: %s", domainName)
+	// 按公共后缀列表拆分子域名、主体和有效顶级域名，如 www.abc.com.cn -> www / abc / com.cn
+	parts, err := s.suffixes.Split(asciiName)
+	if err != nil {
+		return nil, err
 	}
 
-	// 获取TLD（最后一部分）
-	tld := parts[len(parts)-1]
+	tld := parts.Suffix
+	domainName = parts.Domain()
 
-	// 获取域名主体（不含TLD）
-	name := strings.Join(parts[:len(parts)-1], ".")
+	// 结构和长度按Unicode形式计算，如 中文.com 的长度为2
+	unicodeName, err := idna.Lookup.ToUnicode(domainName)
+	if err != nil {
+		return nil, fmt.Errorf("转换域名 %s 为Unicode形式失败: %w", domainName, err)
+	}
+	name, err := idna.Lookup.ToUnicode(parts.Label)
+	if err != nil {
+		return nil, fmt.Errorf("转换域名 %s 为Unicode形式失败: %w", domainName, err)
+	}
 
 	// 确定域名结构
 	structure := determineDomainStructure(name)
 
-	// 尝试获取动态属性中的注册和到期日期
-	registerDate := time.Now().AddDate(-1, 0, 0) // 默认：一年前注册
-	expireDate := time.Now().AddDate(1, 0, 0)    // 默认：一年后到期
-
-	// 尝试从动态属性服务获取WHOIS信息
-	dynamicAttrs, err := s.dynamicAttrService.GetDynamicAttributes(domainName)
-	if err == nil && dynamicAttrs != nil {
-		// 解析注册日期
-		if regDateStr, ok := dynamicAttrs["register_date"].(string); ok {
-			if parsedDate, err := time.Parse("2006-01-02", regDateStr); err == nil {
-				registerDate = parsedDate
-			}
-		}
-
-		// 解析到期日期
-		if expDateStr, ok := dynamicAttrs["expire_date"].(string); ok {
-			if parsedDate, err := time.Parse("2006-01-02", expDateStr); err == nil {
-				expireDate = parsedDate
-			}
+	// 号码形态和拼音形态，英文单词（如 change）即使能切分为拼音也不按拼音处理
+	patterns := pattern.Classify(name)
+	var syllables []string
+	if _, isWord := s.words.Lookup(name); !isWord {
+		syllables = pinyin.Segment(name)
+		if tag := pinyin.Classify(name); tag != "" {
+			patterns = append(patterns, tag)
 		}
 	}
 
-	// 创建域名对象
+	// 创建域名对象，注册和到期日期由 applyRegistrationDates 按动态属性更新
 	domain := &model.Domain{
 		Name:         domainName,
+		UnicodeName:  unicodeName,
 		TLD:          tld,
-		Length:       len(name),
+		Subdomain:    parts.Subdomain,
+		Length:       utf8.RuneCountInString(name),
 		Structure:    structure,
-		RegisterDate: registerDate,
-		ExpireDate:   expireDate,
+		Patterns:     patterns,
+		Syllables:    syllables,
+		RegisterDate: now.AddDate(-1, 0, 0), // 默认：一年前注册
+		ExpireDate:   now.AddDate(1, 0, 0),  // 默认：一年后到期
 	}
 
 	return domain, nil
 }
 
+// applyRegistrationDates 使用动态属性中WHOIS信息的注册和到期日期，没有或格式错误时保留默认值
+func applyRegistrationDates(domain *model.Domain, dynamicAttrs map[string]interface{}) {
+	// 解析注册日期
+	if regDateStr, ok := dynamicAttrs["register_date"].(string); ok {
+		if parsedDate, err := time.Parse("2006-01-02", regDateStr); err == nil {
+			domain.RegisterDate = parsedDate
+		}
+	}
+
+	// 解析到期日期
+	if expDateStr, ok := dynamicAttrs["expire_date"].(string); ok {
+		if parsedDate, err := time.Parse("2006-01-02", expDateStr); err == nil {
+			domain.ExpireDate = parsedDate
+		}
+	}
+}
+
+// unicodeSuffix 返回域名后缀的Unicode形式，取自解析时得到的 UnicodeName，如 中文.中国 的后缀为 中国
+func unicodeSuffix(domain *model.Domain) string {
+	labels := strings.Split(domain.UnicodeName, ".")
+	n := strings.Count(domain.TLD, ".") + 1
+	if n > len(labels) {
+		return domain.TLD
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
 // determineDomainStructure 确定域名的结构类型
 func determineDomainStructure(name string) string {
 	// 检查是否为纯数字
@@ -445,6 +573,11 @@ func determineDomainStructure(name string) string {
 		return "数字字母混合"
 	}
 
+	// 检查汉字结构
+	if structure := determineHanStructure(name); structure != "" {
+		return structure
+	}
+
 	// 检查是否包含连字符
 	if strings.Contains(name, "-") {
 		return "含连字符"
@@ -453,3 +586,33 @@ func determineDomainStructure(name string) string {
 	// 其他情况
 	return "其他"
 }
+
+// determineHanStructure 确定含汉字域名的结构类型，不含汉字或含其他字符时返回空字符串
+func determineHanStructure(name string) string {
+	var hasHan, hasLetter, hasDigit bool
+	for _, r := range name {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			hasHan = true
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		default:
+			return ""
+		}
+	}
+
+	switch {
+	case !hasHan:
+		return ""
+	case hasLetter && hasDigit:
+		return "汉字字母数字混合"
+	case hasLetter:
+		return "汉字字母混合"
+	case hasDigit:
+		return "汉字数字混合"
+	default:
+		return "纯汉字"
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"domainweb/internal/config"
+	"domainweb/internal/dict"
+	"domainweb/internal/psl"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+// newTestDomainService 使用已迁移的数据库和确定性模拟数据源创建估价服务
+func newTestDomainService(t *testing.T, db *sql.DB) *DomainService {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Dynamic.MockMode = config.MockModeDeterministic
+	providers, err := NewDefaultProviderRegistry(cfg.Dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := providers.Configure(cfg.Dynamic.Providers); err != nil {
+		t.Fatal(err)
+	}
+	suffixes, err := psl.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	words, err := dict.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dynamic := NewDynamicAttributeService(providers, cfg.Dynamic.CacheTTLDuration(), cfg.Dynamic.TimeoutDuration())
+	audit := NewAuditService(repository.NewAuditRepository(db))
+	catalog := NewAttributeCatalog(repository.NewDomainRepository(db, repository.SQLite), cfg.Estimation.AttributeRefreshDuration())
+	ruleSets := NewRuleSetService(repository.NewRuleSetRepository(db), catalog, audit, cfg.Estimation)
+	s := NewDomainService(ruleSets, dynamic, cfg.Estimation, suffixes, words)
+	s.SetClock(NewClock(cfg.Dynamic.MockMode))
+	return s
+}
+
+func TestEstimateDomainDeterministic(t *testing.T) {
+	ctx := context.Background()
+	s := newTestDomainService(t, testutil.OpenMigratedSQLite(t))
+
+	first, err := s.EstimateDomain(ctx, "ABC.COM")
+	if err != nil {
+		t.Fatalf("EstimateDomain() error = %v", err)
+	}
+	if first.Domain != "abc.com" {
+		t.Errorf("Domain = %q, want abc.com", first.Domain)
+	}
+	if !first.EstimationDate.Equal(mockEpoch) {
+		t.Errorf("EstimationDate = %v, want %v", first.EstimationDate, mockEpoch)
+	}
+	if first.Price <= 0 || first.RuleSetVersion == 0 {
+		t.Errorf("Price, RuleSetVersion = %v, %d", first.Price, first.RuleSetVersion)
+	}
+
+	base := map[string]string{}
+	for _, detail := range first.BaseAttributes {
+		base[detail.Name] = detail.Value
+	}
+	want := map[string]string{"com后缀": "com", "3位长度": "3", "纯字母结构": "纯字母", "顺子形态": "ABC"}
+	if !reflect.DeepEqual(base, want) {
+		t.Errorf("BaseAttributes = %v, want %v", base, want)
+	}
+	if len(first.OtherAttributes) == 0 {
+		t.Error("没有动态属性")
+	}
+
+	// 确定性模式下同一个域名的估价结果不变
+	second, err := s.EstimateDomain(ctx, "abc.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("两次估价结果不同:\n%+v\n%+v", first, second)
+	}
+}
+
+func TestEstimateDomainInputForms(t *testing.T) {
+	ctx := context.Background()
+	s := newTestDomainService(t, testutil.OpenMigratedSQLite(t))
+
+	// 网址形式的输入按其中的主机名估价
+	inputs := []string{
+		"abc.com",
+		" abc.com. ",
+		"HTTP://ABC.COM/x",
+		"https://abc.com:8080/x",
+		"https://user@abc.com/x?y=1#z",
+		"abc.com?x=1",
+		"abc.com#f",
+		"abc.com:8080",
+		"abc.com/path",
+	}
+	for _, input := range inputs {
+		result, err := s.EstimateDomain(ctx, input)
+		if err != nil {
+			t.Errorf("EstimateDomain(%q) error = %v", input, err)
+			continue
+		}
+		if result.Domain != "abc.com" {
+			t.Errorf("EstimateDomain(%q).Domain = %q, want abc.com", input, result.Domain)
+		}
+	}
+}
+
+func TestEstimateDomainInvalid(t *testing.T) {
+	s := newTestDomainService(t, testutil.OpenMigratedSQLite(t))
+	for _, domain := range []string{"", "com", "abc..com"} {
+		if _, err := s.EstimateDomain(context.Background(), domain); err == nil {
+			t.Errorf("EstimateDomain(%q) error = nil, want error", domain)
+		}
+	}
+}
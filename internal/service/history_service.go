@@ -11,37 +11,79 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
+
 	"domainweb/internal/model"
 	"domainweb/internal/repository"
+
+	"golang.org/x/net/idna"
 )
 
+// ErrHistoryNotFound 表示历史记录不存在
+var ErrHistoryNotFound = errors.New("历史记录不存在")
+
 // HistoryService 处理查询历史的业务逻辑
 type HistoryService struct {
-	repo *repository.HistoryRepository
+	repo         repository.HistoryRepository
+	defaultLimit int // 默认返回的历史记录条数
 }
 
 // NewHistoryService 创建一个新的HistoryService实例
-func NewHistoryService(repo *repository.HistoryRepository) *HistoryService {
-	return &HistoryService{repo: repo}
+func NewHistoryService(repo repository.HistoryRepository, defaultLimit int) *HistoryService {
+	return &HistoryService{repo: repo, defaultLimit: defaultLimit}
 }
 
 // SaveHistory 保存查询历史记录
-func (s *HistoryService) SaveHistory(result *model.EstimationResult) error {
+func (s *HistoryService) SaveHistory(ctx context.Context, result *model.EstimationResult) error {
 	record := &model.HistoryRecord{
 		Domain:         result.Domain,
 		Grade:          result.Grade,
 		Price:          result.Price,
+		RuleSetVersion: result.RuleSetVersion,
 		EstimationDate: result.EstimationDate,
+		Inputs:         result.Inputs,
 	}
 
-	return s.repo.SaveHistory(record)
+	return s.repo.SaveHistory(ctx, record)
+}
+
+// SaveHistories 批量保存查询历史记录，只执行一次批量写入
+func (s *HistoryService) SaveHistories(ctx context.Context, results []*model.EstimationResult) error {
+	records := make([]model.HistoryRecord, 0, len(results))
+	for _, result := range results {
+		records = append(records, model.HistoryRecord{
+			Domain:         result.Domain,
+			Grade:          result.Grade,
+			Price:          result.Price,
+			RuleSetVersion: result.RuleSetVersion,
+			EstimationDate: result.EstimationDate,
+			Inputs:         result.Inputs,
+		})
+	}
+
+	return s.repo.SaveHistoryBatch(ctx, records)
 }
 
 // GetHistory 获取查询历史记录
-func (s *HistoryService) GetHistory(domain string, limit int) ([]model.HistoryRecord, error) {
+// 历史记录按规范化的小写punycode域名保存，查询的域名同样规范化后再匹配
+func (s *HistoryService) GetHistory(ctx context.Context, domain string, limit int) ([]model.HistoryRecord, error) {
 	if limit <= 0 {
-		limit = 50 // 默认限制为50条记录
+		limit = s.defaultLimit
+	}
+	if asciiName, err := idna.Lookup.ToASCII(strings.TrimSpace(domain)); err == nil {
+		domain = asciiName
 	}
 
-	return s.repo.GetHistory(domain, limit)
+	return s.repo.GetHistory(ctx, domain, limit)
+}
+
+// Get 获取单条查询历史记录
+func (s *HistoryService) Get(ctx context.Context, id int64) (*model.HistoryRecord, error) {
+	record, err := s.repo.GetHistoryRecord(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrHistoryNotFound
+	}
+	return record, err
 }
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"domainweb/internal/config"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+)
+
+var (
+	// ErrJobNotFound 表示任务不存在
+	ErrJobNotFound = errors.New("任务不存在")
+	// ErrJobFinished 表示任务已经结束，无法取消
+	ErrJobFinished = errors.New("任务已结束")
+	// ErrJobNotFinished 表示任务尚未结束，还不能下载结果
+	ErrJobNotFinished = errors.New("任务尚未结束")
+)
+
+// JobService 管理异步批量估价任务
+// 任务和每个域名的估价进度都保存在数据库中，服务重启后通过 Resume 继续执行未完成的任务
+type JobService struct {
+	repo           repository.JobRepository
+	domainService  *DomainService
+	historyService *HistoryService
+	chunkSize      int           // 每次估价并保存进度的域名数量
+	maxSize        int           // 单个任务的最大域名数量
+	slots          chan struct{} // 限制同时执行的任务数量
+
+	ctx     context.Context // 所有任务的上级上下文，Shutdown 时取消
+	stop    context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // 已启动任务的取消函数
+}
+
+// NewJobService 创建一个新的JobService实例
+func NewJobService(repo repository.JobRepository, domainService *DomainService, historyService *HistoryService, cfg config.JobsConfig) *JobService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &JobService{
+		repo:           repo,
+		domainService:  domainService,
+		historyService: historyService,
+		chunkSize:      cfg.ChunkSize,
+		maxSize:        cfg.MaxSize,
+		slots:          make(chan struct{}, cfg.MaxRunning),
+		ctx:            ctx,
+		stop:           stop,
+		running:        make(map[string]context.CancelFunc),
+	}
+}
+
+// MaxSize 返回单个任务允许的最大域名数量
+func (s *JobService) MaxSize() int {
+	return s.maxSize
+}
+
+// Submit 创建任务并在后台开始执行，ctx 只用于保存任务，任务的执行不受其取消的影响
+func (s *JobService) Submit(ctx context.Context, domains []string) (*model.Job, error) {
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("域名列表为空")
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &model.Job{
+		ID:        id,
+		Status:    model.JobStatusPending,
+		Total:     len(domains),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateJob(ctx, job, domains); err != nil {
+		return nil, err
+	}
+
+	s.start(job.ID)
+	return job, nil
+}
+
+// Get 获取任务及其进度
+func (s *JobService) Get(ctx context.Context, id string) (*model.Job, error) {
+	job, err := s.repo.GetJob(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+// Cancel 取消未结束的任务，已经保存的估价结果会保留
+func (s *JobService) Cancel(ctx context.Context, id string) (*model.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return job, ErrJobFinished
+	}
+
+	if err := s.repo.UpdateJobStatus(ctx, id, model.JobStatusCancelled, ""); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	return s.Get(ctx, id)
+}
+
+// Results 获取已结束任务的所有域名及估价结果
+func (s *JobService) Results(ctx context.Context, id string) (*model.Job, []model.JobItem, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !job.Finished() {
+		return job, nil, ErrJobNotFinished
+	}
+
+	items, err := s.repo.GetJobItems(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, items, nil
+}
+
+// Resume 重新启动所有未完成的任务，返回启动的任务数量
+func (s *JobService) Resume(ctx context.Context) (int, error) {
+	jobs, err := s.repo.ListUnfinishedJobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		s.start(job.ID)
+	}
+	return len(jobs), nil
+}
+
+// Shutdown 停止所有执行中的任务并等待其退出
+// 任务保持未完成状态，下次启动时由 Resume 继续执行
+func (s *JobService) Shutdown(ctx context.Context) error {
+	s.stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start 在后台执行任务，同时执行的任务数量受 slots 限制
+func (s *JobService) start(id string) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.running[id] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, id)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		// 等待空闲的执行槽位
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		case <-ctx.Done():
+			return
+		}
+
+		// 取消或关闭服务导致的中断不视为任务失败
+		if err := s.run(ctx, id); err != nil && ctx.Err() == nil {
+			log.Printf("估价任务 %s 执行失败: %v", id, err)
+			if err := s.repo.UpdateJobStatus(ctx, id, model.JobStatusFailed, err.Error()); err != nil {
+				log.Printf("更新估价任务 %s 状态失败: %v", id, err)
+			}
+		}
+	}()
+}
+
+// run 分批估价任务中未处理的域名，每批完成后保存进度
+func (s *JobService) run(ctx context.Context, id string) error {
+	if err := s.repo.UpdateJobStatus(ctx, id, model.JobStatusRunning, ""); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		items, err := s.repo.GetPendingJobItems(ctx, id, s.chunkSize)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return s.repo.UpdateJobStatus(ctx, id, model.JobStatusCompleted, "")
+		}
+
+		domains := make([]string, len(items))
+		for i, item := range items {
+			domains[i] = item.Domain
+		}
+
+		estimations, err := s.domainService.EstimateDomains(ctx, domains)
+		if err != nil {
+			return err
+		}
+		// 本批估价被中断时丢弃结果，恢复后重新估价
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		results := make([]*model.EstimationResult, 0, len(items))
+		for i, estimation := range estimations {
+			if estimation.Result != nil {
+				items[i].Status = model.JobItemDone
+				items[i].Result = estimation.Result
+				results = append(results, estimation.Result)
+			} else {
+				items[i].Status = model.JobItemFailed
+				items[i].Error = estimation.Error
+			}
+		}
+
+		if err := s.repo.SaveJobItemResults(ctx, id, items); err != nil {
+			return err
+		}
+		if err := s.historyService.SaveHistories(ctx, results); err != nil {
+			log.Printf("保存估价任务 %s 的查询历史失败: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// newJobID 生成随机的任务ID
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成任务ID失败: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
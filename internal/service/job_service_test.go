@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"domainweb/internal/config"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/testutil"
+)
+
+// newTestJobService 创建每批估价一个域名的任务服务，返回的 block 数据源在查询 block.com 时阻塞直到任务被取消
+func newTestJobService(t *testing.T) (*JobService, repository.JobRepository, *HistoryService, <-chan struct{}) {
+	t.Helper()
+	db := testutil.OpenMigratedSQLite(t)
+	domains := newTestDomainService(t, db)
+
+	blocked := make(chan struct{}, 1)
+	err := domains.dynamicAttrService.Registry().Register(&ProviderFunc{
+		ProviderName: "block",
+		ProviderKeys: []string{"block"},
+		FetchFunc: func(ctx context.Context, domain string) (map[string]interface{}, error) {
+			if domain != "block.com" {
+				return nil, nil
+			}
+			blocked <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewJobRepository(db)
+	history := NewHistoryService(repository.NewHistoryRepository(db), 10)
+	jobs := NewJobService(repo, domains, history, config.JobsConfig{MaxRunning: 2, ChunkSize: 1, MaxSize: 100})
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := jobs.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	})
+	return jobs, repo, history, blocked
+}
+
+// waitJob 等待任务结束
+func waitJob(t *testing.T, s *JobService, id string) *model.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := s.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.Finished() {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("任务 %s 没有结束，状态 %s", id, job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJobServiceRunToCompletion(t *testing.T) {
+	ctx := context.Background()
+	s, _, history, _ := newTestJobService(t)
+
+	if _, err := s.Submit(ctx, nil); err == nil {
+		t.Error("Submit(nil) error = nil, want error")
+	}
+
+	job, err := s.Submit(ctx, []string{"abc.com", "ABC.NET", "abc..com"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if job.Status != model.JobStatusPending || job.Total != 3 {
+		t.Errorf("Submit() = %+v", job)
+	}
+
+	job = waitJob(t, s, job.ID)
+	if job.Status != model.JobStatusCompleted || job.Done != 2 || job.Failed != 1 || job.FinishedAt == nil {
+		t.Errorf("任务结束时 = %+v", job)
+	}
+
+	_, items, err := s.Results(ctx, job.ID)
+	if err != nil || len(items) != 3 {
+		t.Fatalf("Results() = %v, %v", items, err)
+	}
+	if items[0].Status != model.JobItemDone || items[0].Result == nil || items[0].Result.Domain != "abc.com" {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+	if items[1].Status != model.JobItemDone || items[1].Domain != "ABC.NET" || items[1].Result.Domain != "abc.net" {
+		t.Errorf("items[1] = %+v", items[1])
+	}
+	if items[2].Status != model.JobItemFailed || items[2].Error == "" {
+		t.Errorf("items[2] = %+v", items[2])
+	}
+
+	// 估价成功的域名写入查询历史
+	if records, err := history.GetHistory(ctx, "abc.net", 10); err != nil || len(records) != 1 {
+		t.Errorf("GetHistory(abc.net) = %v, %v", records, err)
+	}
+
+	if _, err := s.Cancel(ctx, job.ID); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("Cancel() 已结束的任务 error = %v, want ErrJobFinished", err)
+	}
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestJobServiceCancel(t *testing.T) {
+	ctx := context.Background()
+	s, _, _, blocked := newTestJobService(t)
+
+	job, err := s.Submit(ctx, []string{"abc.com", "block.com", "xyz.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一个域名已经估价完成，第二个域名正在估价
+	select {
+	case <-blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("任务没有开始估价 block.com")
+	}
+	if _, _, err := s.Results(ctx, job.ID); !errors.Is(err, ErrJobNotFinished) {
+		t.Errorf("Results() 未结束的任务 error = %v, want ErrJobNotFinished", err)
+	}
+
+	job, err = s.Cancel(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if job.Status != model.JobStatusCancelled {
+		t.Errorf("Cancel() status = %s", job.Status)
+	}
+
+	// 已经保存的估价结果保留，被中断的一批不保存
+	_, items, err := s.Results(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := []string{items[0].Status, items[1].Status, items[2].Status}
+	if statuses[0] != model.JobItemDone || statuses[1] != model.JobItemPending || statuses[2] != model.JobItemPending {
+		t.Errorf("取消后的域名状态 = %v", statuses)
+	}
+	if job = waitJob(t, s, job.ID); job.Status != model.JobStatusCancelled || job.Done != 1 {
+		t.Errorf("取消后的任务 = %+v", job)
+	}
+}
+
+func TestJobServiceResume(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, _ := newTestJobService(t)
+
+	// 模拟重启前已经开始执行的任务：一个域名已完成，任务状态为执行中
+	now := time.Now()
+	job := &model.Job{ID: "resumed", Status: model.JobStatusPending, Total: 2, CreatedAt: now, UpdatedAt: now}
+	if err := repo.CreateJob(ctx, job, []string{"abc.com", "xyz.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateJobStatus(ctx, job.ID, model.JobStatusRunning, ""); err != nil {
+		t.Fatal(err)
+	}
+	items, err := repo.GetPendingJobItems(ctx, job.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items[0].Status, items[0].Result = model.JobItemDone, &model.EstimationResult{Domain: "abc.com", Price: 1}
+	if err := repo.SaveJobItemResults(ctx, job.ID, items); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.Resume(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Resume() = %d, %v, want 1", n, err)
+	}
+	job = waitJob(t, s, job.ID)
+	if job.Status != model.JobStatusCompleted || job.Done != 2 {
+		t.Errorf("恢复后的任务 = %+v", job)
+	}
+
+	// 重启前完成的域名不重新估价
+	_, results, err := s.Results(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Result.Price != 1 || results[1].Result == nil || results[1].Result.Domain != "xyz.com" {
+		t.Errorf("Results() = %+v, %+v", results[0].Result, results[1].Result)
+	}
+
+	if n, err := s.Resume(ctx); err != nil || n != 0 {
+		t.Errorf("Resume() = %d, %v, want 0", n, err)
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"domainweb/internal/config"
+	"domainweb/internal/dict"
+	"domainweb/internal/rdap"
+	"domainweb/internal/whois"
+)
+
+// NewDefaultProviderRegistry 创建注册了所有内置数据源的ProviderRegistry
+// 后注册的数据源优先级更高，同名属性会覆盖先注册的模拟数据
+func NewDefaultProviderRegistry(cfg config.DynamicConfig) (*ProviderRegistry, error) {
+	rdapClient, err := newRDAPClient(cfg.RDAP)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := dict.Default()
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewProviderRegistry()
+	for _, p := range mockProviders(newMockSource(cfg.MockMode, cfg.MockSeed, words)) {
+		// 内置数据源名称固定且互不相同，不会注册失败
+		_ = r.Register(p)
+	}
+
+	_ = r.Register(NewDictionaryProvider(words))
+	_ = r.Register(NewBrandabilityProvider(words))
+
+	whoisProvider := NewWhoisProvider(newWhoisClient(cfg.Whois))
+	var fallback *WhoisProvider
+	if cfg.RDAP.FallbackToWhois {
+		fallback = whoisProvider
+	}
+	_ = r.Register(whoisProvider)
+	_ = r.Register(NewRDAPProvider(rdapClient, fallback))
+
+	// 实时数据源需要访问网络，默认禁用，在配置中启用；rdap已包含WHOIS回退，通常不必单独启用whois
+	// 确定性模式下结果只取决于域名和种子，配置中启用实时数据源会在校验时报错
+	for _, name := range config.LiveProviders {
+		_ = r.SetEnabled(name, false)
+	}
+
+	return r, nil
+}
+
+// newWhoisClient 按配置创建WHOIS客户端
+func newWhoisClient(cfg config.WhoisConfig) *whois.Client {
+	client := whois.NewClient()
+	for tld, server := range cfg.Servers {
+		client.Servers[tld] = server
+	}
+	if cfg.Timeout > 0 {
+		client.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	if cfg.MaxReferrals >= 0 {
+		client.MaxReferrals = cfg.MaxReferrals
+	}
+	if !cfg.Discovery {
+		client.DiscoveryServer = ""
+	}
+	return client
+}
+
+// newRDAPClient 按配置创建RDAP客户端
+func newRDAPClient(cfg config.RDAPConfig) (*rdap.Client, error) {
+	bootstrap, err := rdap.LoadBootstrap(cfg.BootstrapFile)
+	if err != nil {
+		return nil, err
+	}
+
+	client := rdap.NewClient(bootstrap)
+	for tld, server := range cfg.Servers {
+		client.Servers[tld] = server
+	}
+	if cfg.Timeout > 0 {
+		client.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	return client, nil
+}
+
+// mockEpoch 是确定性模式下模拟日期的参照时间
+var mockEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewClock 返回估价使用的参照时钟：确定性模式下固定为模拟日期的参照时间，
+// 使注册年数、到期天数等随时间变化的变量同样可复现；随机模式下为当前时间
+func NewClock(mode string) func() time.Time {
+	if mode == config.MockModeDeterministic {
+		return func() time.Time { return mockEpoch }
+	}
+	return time.Now
+}
+
+// mockSource 为模拟数据源提供"随机"数值
+// 随机模式沿用基于当前时间的取值；确定性模式由域名、用途和种子的哈希决定，
+// 同一域名在相同种子下总是得到相同的模拟数据
+type mockSource struct {
+	deterministic bool
+	seed          int64
+	words         *dict.Dictionary // 由英文单词组成的域名模拟出更高的排名和搜索量
+	now           func() time.Time // 模拟日期的参照时间
+}
+
+// newMockSource 创建一个新的mockSource实例
+func newMockSource(mode string, seed int64, words *dict.Dictionary) *mockSource {
+	return &mockSource{
+		deterministic: mode == config.MockModeDeterministic,
+		seed:          seed,
+		words:         words,
+		now:           NewClock(mode),
+	}
+}
+
+// intn 返回 [0, n) 范围内的模拟数值，salt 用于区分同一域名的不同用途
+func (m *mockSource) intn(domain, salt string, n int) int {
+	if !m.deterministic {
+		return int(time.Now().Unix() % int64(n))
+	}
+
+	h := fnv.New64a()
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(m.seed))
+	h.Write(seed[:])
+	h.Write([]byte(strings.ToLower(domain)))
+	h.Write([]byte{0})
+	h.Write([]byte(salt))
+	return int(h.Sum64() % uint64(n))
+}
+
+// mockProviders 返回基于模拟数据的内置数据源
+func mockProviders(m *mockSource) []DynamicAttributeProvider {
+	return []DynamicAttributeProvider{
+		&ProviderFunc{
+			ProviderName: "mock_whois",
+			ProviderKeys: []string{"register_date", "expire_date", "registrar"},
+			FetchFunc:    m.whoisInfo,
+		},
+		&ProviderFunc{
+			ProviderName: "mock_alexa",
+			ProviderKeys: []string{"alexa_rank"},
+			FetchFunc:    m.alexaRank,
+		},
+		&ProviderFunc{
+			ProviderName: "mock_search_volume",
+			ProviderKeys: []string{"search_volume"},
+			FetchFunc:    m.searchVolume,
+		},
+		&ProviderFunc{
+			ProviderName: "mock_related_domains",
+			ProviderKeys: []string{"related_domain_*"},
+			FetchFunc:    m.relatedDomainsStatus,
+		},
+		&ProviderFunc{
+			ProviderName: "mock_social",
+			ProviderKeys: []string{"tieba_posts", "baike_index", "dict_record", "search_360_index", "media_index", "social_index", "taobao_products"},
+			FetchFunc:    m.socialAndEcommerceData,
+		},
+	}
+}
+
+// whoisInfo 模拟获取域名的WHOIS信息
+func (m *mockSource) whoisInfo(ctx context.Context, domain string) (map[string]interface{}, error) {
+	// 在实际系统中，这里应该调用WHOIS API或解析WHOIS服务器响应
+	// 这里使用模拟数据
+	result := make(map[string]interface{})
+
+	// 模拟数据：根据域名生成一些随机但看起来合理的注册信息
+	domainHash := m.intn(domain, "whois", 10)
+	if strings.Contains(domain, "a") {
+		domainHash = (domainHash + 1) % 10
+	}
+	if strings.Contains(domain, "e") {
+		domainHash = (domainHash + 2) % 10
+	}
+
+	// 注册日期：1-10年前
+	registerYearsAgo := 1 + domainHash
+	registerDate := m.now().AddDate(-registerYearsAgo, 0, 0)
+	result["register_date"] = registerDate.Format("2006-01-02")
+
+	// 到期日期：1-3年后
+	expireYearsLater := 1 + (domainHash % 3)
+	expireDate := m.now().AddDate(expireYearsLater, 0, 0)
+	result["expire_date"] = expireDate.Format("2006-01-02")
+
+	// 注册商
+	registrars := []string{"GoDaddy", "Namecheap", "Alibaba Cloud", "Tencent Cloud", "NameSilo"}
+	result["registrar"] = registrars[domainHash%len(registrars)]
+
+	return result, nil
+}
+
+// alexaRank 模拟获取域名的Alexa排名
+func (m *mockSource) alexaRank(ctx context.Context, domain string) (map[string]interface{}, error) {
+	// 在实际系统中，这里应该调用Alexa API
+	// 这里使用模拟数据
+
+	// 根据域名长度和字符生成一个看起来合理的排名
+	// 短域名和含有常见词的域名排名更高
+	rank := 1000000 // 默认排名
+
+	// 域名越短，排名越高
+	if len(domain) < 10 {
+		rank = rank / (12 - len(domain))
+	}
+
+	// 由英文单词组成的域名排名更高，单个单词更高
+	switch len(m.words.Segment(strings.Split(domain, ".")[0])) {
+	case 0:
+	case 1:
+		rank = rank / 4
+	default:
+		rank = rank / 2
+	}
+
+	// 添加一些随机性
+	rank = rank + m.intn(domain, "alexa", 10000) - 5000
+	if rank < 100 {
+		rank = 100 + m.intn(domain, "alexa_min", 900)
+	}
+
+	return map[string]interface{}{"alexa_rank": rank}, nil
+}
+
+// searchVolume 模拟获取域名相关关键词的搜索量
+func (m *mockSource) searchVolume(ctx context.Context, domain string) (map[string]interface{}, error) {
+	// 在实际系统中，这里应该调用搜索API，如Google Keyword Planner或百度指数
+	// 这里使用模拟数据
+
+	// 提取域名主体（不含TLD）
+	parts := strings.Split(domain, ".")
+	keyword := parts[0]
+
+	// 根据关键词长度和字符生成一个看起来合理的搜索量
+	volume := 1000 // 默认搜索量
+
+	// 关键词越短，搜索量越高
+	if len(keyword) < 6 {
+		volume = volume * (7 - len(keyword))
+	}
+
+	// 由英文单词组成的关键词搜索量更高，单个单词更高
+	switch len(m.words.Segment(keyword)) {
+	case 0:
+	case 1:
+		volume = volume * 5
+	default:
+		volume = volume * 3
+	}
+
+	// 添加一些随机性
+	volume = volume + m.intn(domain, "search_volume", 1000) - 500
+	if volume < 100 {
+		volume = 100 + m.intn(domain, "search_volume_min", 900)
+	}
+
+	return map[string]interface{}{"search_volume": volume}, nil
+}
+
+// relatedDomainsStatus 模拟获取相关域名的注册状态
+func (m *mockSource) relatedDomainsStatus(ctx context.Context, domain string) (map[string]interface{}, error) {
+	// 在实际系统中，这里应该调用WHOIS API查询相关域名
+	// 这里使用模拟数据
+	result := make(map[string]interface{})
+
+	// 提取域名主体（不含TLD）
+	parts := strings.Split(domain, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("无效的域名格式: %s", domain)
+	}
+
+	domainName := parts[0]
+	mainTLD := parts[len(parts)-1]
+
+	// 检查相关TLD
+	relatedTLDs := []string{"com", "net", "org", "co", "cc", "io", "ai"}
+	for _, tld := range relatedTLDs {
+		if tld == mainTLD {
+			continue // 跳过主域名的TLD
+		}
+
+		// 构建相关域名（用于日志或调试）
+		_ = fmt.Sprintf("%s.%s", domainName, tld)
+
+		// 模拟注册状态：根据域名和TLD生成一个看起来合理的状态
+		// 越常见的TLD，被注册的可能性越高
+		isRegistered := false
+		if tld == "com" || tld == "net" || tld == "org" {
+			isRegistered = (len(domainName) < 6) // 短域名在常见TLD下更可能被注册
+		} else {
+			isRegistered = (len(domainName) < 4) // 非常短的域名在其他TLD下更可能被注册
+		}
+
+		// 添加一些随机性
+		if m.intn(domain, "related_"+tld, 2) == 0 {
+			isRegistered = !isRegistered
+		}
+
+		status := "未注册"
+		if isRegistered {
+			registerYearsAgo := 1 + m.intn(domain, "related_date_"+tld, 5)
+			registerDate := m.now().AddDate(-registerYearsAgo, 0, 0)
+			status = fmt.Sprintf("在 %s 注册", registerDate.Format("2006.01.02"))
+		}
+
+		result[fmt.Sprintf("related_domain_%s", tld)] = status
+	}
+
+	return result, nil
+}
+
+// socialAndEcommerceData 模拟获取社交媒体和电商数据
+func (m *mockSource) socialAndEcommerceData(ctx context.Context, domain string) (map[string]interface{}, error) {
+	// 在实际系统中，这里应该调用各种API获取社交媒体和电商数据
+	// 这里使用模拟数据
+	result := make(map[string]interface{})
+
+	// 提取域名主体（不含TLD）
+	parts := strings.Split(domain, ".")
+	keyword := parts[0]
+
+	// 贴吧数量
+	tiebaPosts := 1000 + len(keyword)*500 + m.intn(domain, "tieba_posts", 10000)
+	result["tieba_posts"] = tiebaPosts
+
+	// 百科系数
+	baikeIndex := 1000 + len(keyword)*200 + m.intn(domain, "baike_index", 5000)
+	result["baike_index"] = baikeIndex
+
+	// 词典记录
+	hasDictRecord := len(keyword) < 6 || m.intn(domain, "dict_record", 2) == 0
+	result["dict_record"] = hasDictRecord
+
+	// 360搜索指数
+	search360Index := 500 + len(keyword)*100 + m.intn(domain, "search_360_index", 2000)
+	result["search_360_index"] = search360Index
+
+	// 传媒系数
+	mediaIndex := 10000 + len(keyword)*1000 + m.intn(domain, "media_index", 100000)
+	result["media_index"] = mediaIndex
+
+	// 社交系数
+	socialIndex := 5000 + len(keyword)*500 + m.intn(domain, "social_index", 50000)
+	result["social_index"] = socialIndex
+
+	// 淘宝商品数量
+	taobaoProducts := 100 + len(keyword)*50 + m.intn(domain, "taobao_products", 1000)
+	result["taobao_products"] = taobaoProducts
+
+	return result, nil
+}
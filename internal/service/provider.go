@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DynamicAttributeProvider 表示一个动态属性数据源，如WHOIS、搜索量、电商数据等
+type DynamicAttributeProvider interface {
+	// Name 返回数据源名称，用于配置启用或禁用，如 "mock_whois"
+	Name() string
+	// Keys 返回数据源产出的属性键，以 * 结尾表示前缀匹配，如 "related_domain_*"
+	Keys() []string
+	// Fetch 获取域名的动态属性
+	Fetch(ctx context.Context, domain string) (map[string]interface{}, error)
+}
+
+// ProviderFunc 将普通函数适配为DynamicAttributeProvider
+type ProviderFunc struct {
+	ProviderName string
+	ProviderKeys []string
+	FetchFunc    func(ctx context.Context, domain string) (map[string]interface{}, error)
+}
+
+// Name 返回数据源名称
+func (p *ProviderFunc) Name() string { return p.ProviderName }
+
+// Keys 返回数据源产出的属性键
+func (p *ProviderFunc) Keys() []string { return p.ProviderKeys }
+
+// Fetch 获取域名的动态属性
+func (p *ProviderFunc) Fetch(ctx context.Context, domain string) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.FetchFunc(ctx, domain)
+}
+
+// ProviderRegistry 管理已注册的动态属性数据源及其启用状态
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers []DynamicAttributeProvider // 按注册顺序保存
+	enabled   map[string]bool
+}
+
+// NewProviderRegistry 创建一个空的ProviderRegistry实例
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{enabled: make(map[string]bool)}
+}
+
+// Register 注册数据源，新注册的数据源默认启用
+func (r *ProviderRegistry) Register(p DynamicAttributeProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.enabled[p.Name()]; ok {
+		return fmt.Errorf("数据源 %s 已注册", p.Name())
+	}
+	r.providers = append(r.providers, p)
+	r.enabled[p.Name()] = true
+	return nil
+}
+
+// SetEnabled 启用或禁用指定的数据源
+func (r *ProviderRegistry) SetEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.enabled[name]; !ok {
+		return fmt.Errorf("未知的数据源: %s", name)
+	}
+	r.enabled[name] = enabled
+	return nil
+}
+
+// Configure 按配置批量设置数据源的启用状态，未出现在配置中的数据源保持不变
+func (r *ProviderRegistry) Configure(settings map[string]bool) error {
+	for name, enabled := range settings {
+		if err := r.SetEnabled(name, enabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enabled 返回所有已启用的数据源
+func (r *ProviderRegistry) Enabled() []DynamicAttributeProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []DynamicAttributeProvider
+	for _, p := range r.providers {
+		if r.enabled[p.Name()] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Names 返回所有已注册数据源的名称及启用状态
+func (r *ProviderRegistry) Names() map[string]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]bool, len(r.enabled))
+	for name, enabled := range r.enabled {
+		result[name] = enabled
+	}
+	return result
+}
+
+// Keys 返回所有已启用数据源产出的属性键（去重并排序）
+func (r *ProviderRegistry) Keys() []string {
+	return collectKeys(r.Enabled())
+}
+
+// AllKeys 返回所有已注册数据源产出的属性键（去重并排序），包括未启用的数据源
+// 用于校验估价规则，使规则不会因为临时禁用某个数据源而失效
+func (r *ProviderRegistry) AllKeys() []string {
+	r.mu.RLock()
+	providers := append([]DynamicAttributeProvider(nil), r.providers...)
+	r.mu.RUnlock()
+	return collectKeys(providers)
+}
+
+// collectKeys 合并数据源声明的属性键
+func collectKeys(providers []DynamicAttributeProvider) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, p := range providers {
+		for _, key := range p.Keys() {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// declaresKey 判断属性键是否在数据源声明的键中
+func declaresKey(p DynamicAttributeProvider, key string) bool {
+	for _, k := range p.Keys() {
+		if k == key || (strings.HasSuffix(k, "*") && strings.HasPrefix(key, strings.TrimSuffix(k, "*"))) {
+			return true
+		}
+	}
+	return false
+}
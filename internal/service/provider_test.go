@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProvider 返回固定的属性并记录调用次数
+func fakeProvider(name string, keys []string, attrs map[string]interface{}, err error, calls *int32) *ProviderFunc {
+	return &ProviderFunc{
+		ProviderName: name,
+		ProviderKeys: keys,
+		FetchFunc: func(ctx context.Context, domain string) (map[string]interface{}, error) {
+			if calls != nil {
+				atomic.AddInt32(calls, 1)
+			}
+			return attrs, err
+		},
+	}
+}
+
+func TestProviderRegistry(t *testing.T) {
+	r := NewProviderRegistry()
+	if err := r.Register(fakeProvider("a", []string{"x", "shared"}, nil, nil, nil)); err != nil {
+		t.Fatalf("Register(a) error = %v", err)
+	}
+	if err := r.Register(fakeProvider("b", []string{"related_*", "shared"}, nil, nil, nil)); err != nil {
+		t.Fatalf("Register(b) error = %v", err)
+	}
+	if err := r.Register(fakeProvider("a", nil, nil, nil, nil)); err == nil {
+		t.Error("Register(a) 重复注册 error = nil, want error")
+	}
+
+	// 新注册的数据源默认启用
+	if got := r.Names(); !reflect.DeepEqual(got, map[string]bool{"a": true, "b": true}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if got := r.Keys(); !reflect.DeepEqual(got, []string{"related_*", "shared", "x"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+
+	if err := r.SetEnabled("b", false); err != nil {
+		t.Fatalf("SetEnabled(b) error = %v", err)
+	}
+	if err := r.SetEnabled("missing", true); err == nil {
+		t.Error("SetEnabled(missing) error = nil, want error")
+	}
+	if enabled := r.Enabled(); len(enabled) != 1 || enabled[0].Name() != "a" {
+		t.Errorf("Enabled() = %v, want [a]", enabled)
+	}
+	if got := r.Keys(); !reflect.DeepEqual(got, []string{"shared", "x"}) {
+		t.Errorf("Keys() = %v, want [shared x]", got)
+	}
+	// 规则校验使用包括禁用数据源在内的所有属性键
+	if got := r.AllKeys(); !reflect.DeepEqual(got, []string{"related_*", "shared", "x"}) {
+		t.Errorf("AllKeys() = %v", got)
+	}
+
+	// 未出现在配置中的数据源保持不变
+	if err := r.Configure(map[string]bool{"b": true}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, map[string]bool{"a": true, "b": true}) {
+		t.Errorf("Names() = %v", got)
+	}
+	err := r.Configure(map[string]bool{"unknown": true})
+	if err == nil || !strings.Contains(err.Error(), "未知的数据源: unknown") {
+		t.Errorf("Configure(unknown) error = %v", err)
+	}
+}
+
+func TestDynamicAttributeServiceProviders(t *testing.T) {
+	var calls, disabledCalls int32
+	r := NewProviderRegistry()
+	_ = r.Register(fakeProvider("base", []string{"rank", "shared"},
+		map[string]interface{}{"rank": 10, "shared": "base", "undeclared": 1}, nil, &calls))
+	_ = r.Register(fakeProvider("related", []string{"related_*", "shared"},
+		map[string]interface{}{"related_net": "已注册", "shared": "related", "relatedx": 1}, nil, &calls))
+	_ = r.Register(fakeProvider("broken", []string{"broken"}, nil, errors.New("连接失败"), &calls))
+	_ = r.Register(fakeProvider("disabled", []string{"off"}, map[string]interface{}{"off": 1}, nil, &disabledCalls))
+	_ = r.SetEnabled("disabled", false)
+
+	s := NewDynamicAttributeService(r, time.Hour, time.Second)
+	got, err := s.GetDynamicAttributes(context.Background(), "abc.com")
+	if err != nil {
+		t.Fatalf("GetDynamicAttributes() error = %v", err)
+	}
+	// 未声明的属性被忽略，后注册的数据源覆盖同名属性，失败的数据源不影响其他结果
+	want := map[string]interface{}{"rank": 10, "related_net": "已注册", "shared": "related"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDynamicAttributes() = %v, want %v", got, want)
+	}
+	if disabledCalls != 0 {
+		t.Error("禁用的数据源被调用")
+	}
+
+	// 结果被缓存
+	if _, err := s.GetDynamicAttributes(context.Background(), "abc.com"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("数据源被调用 %d 次，want 3", calls)
+	}
+
+	// 所有数据源都失败时返回错误
+	r = NewProviderRegistry()
+	_ = r.Register(fakeProvider("broken", []string{"broken"}, nil, errors.New("连接失败"), nil))
+	s = NewDynamicAttributeService(r, time.Hour, time.Second)
+	if _, err := s.GetDynamicAttributes(context.Background(), "abc.com"); err == nil || !strings.Contains(err.Error(), "连接失败") {
+		t.Errorf("GetDynamicAttributes() error = %v, want 连接失败", err)
+	}
+}
+
+func TestDynamicAttributeServiceCancelled(t *testing.T) {
+	r := NewProviderRegistry()
+	_ = r.Register(fakeProvider("base", []string{"rank"}, map[string]interface{}{"rank": 10}, nil, nil))
+	s := NewDynamicAttributeService(r, time.Hour, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.GetDynamicAttributes(ctx, "abc.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetDynamicAttributes() error = %v, want context.Canceled", err)
+	}
+	// 取消的请求不写入缓存
+	if got, err := s.GetDynamicAttributes(context.Background(), "abc.com"); err != nil || got["rank"] != 10 {
+		t.Errorf("GetDynamicAttributes() = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"domainweb/internal/rdap"
+)
+
+// RDAPProvider 通过RDAP获取域名的注册信息，RDAP不可用时回退到WHOIS
+type RDAPProvider struct {
+	client   *rdap.Client
+	fallback *WhoisProvider // 为nil时不回退
+	noServer sync.Map       // 已记录过没有RDAP服务地址的顶级域，每个顶级域只记录一次
+}
+
+// NewRDAPProvider 创建一个新的RDAPProvider实例
+func NewRDAPProvider(client *rdap.Client, fallback *WhoisProvider) *RDAPProvider {
+	return &RDAPProvider{client: client, fallback: fallback}
+}
+
+// Name 返回数据源名称
+func (p *RDAPProvider) Name() string { return "rdap" }
+
+// Keys 返回数据源产出的属性键
+func (p *RDAPProvider) Keys() []string {
+	return []string{"registered", "register_date", "expire_date", "updated_date", "registrar", "domain_status",
+		"name_servers", "registration_source"}
+}
+
+// Fetch 查询域名的RDAP注册数据
+func (p *RDAPProvider) Fetch(ctx context.Context, domain string) (map[string]interface{}, error) {
+	record, err := p.client.Lookup(ctx, domain)
+	if errors.Is(err, rdap.ErrNoService) {
+		tld := domain[strings.Index(domain, ".")+1:]
+		if _, logged := p.noServer.LoadOrStore(tld, true); !logged {
+			log.Printf("RDAP引导文件中没有 %s 的服务地址（内置文件只包含部分顶级域，可通过 dynamic.rdap.bootstrapFile 指定IANA的完整文件）", tld)
+		}
+	}
+	switch {
+	case err == nil:
+		result := registrationAttributes(record.Registrar, record.Registered, record.Expires, record.LastChanged,
+			record.Status, record.NameServers)
+		result["registration_source"] = "rdap"
+		return result, nil
+	case errors.Is(err, rdap.ErrNotFound):
+		return map[string]interface{}{"registered": false, "registration_source": "rdap"}, nil
+	case p.fallback == nil || ctx.Err() != nil:
+		return nil, err
+	}
+
+	// 没有RDAP服务或RDAP服务器不可用时回退到WHOIS
+	result, whoisErr := p.fallback.Fetch(ctx, domain)
+	if whoisErr != nil {
+		return nil, errors.Join(err, whoisErr)
+	}
+	result["registration_source"] = "whois"
+	return result, nil
+}
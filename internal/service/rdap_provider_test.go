@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"domainweb/internal/config"
+	"domainweb/internal/rdap"
+	"domainweb/internal/whois"
+)
+
+// startWhoisServer 启动一个按查询内容返回固定响应的WHOIS服务器，返回其地址
+func startWhoisServer(t *testing.T, responses map[string]string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				query, _ := bufio.NewReader(conn).ReadString('\n')
+				conn.Write([]byte(responses[strings.TrimSpace(query)]))
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// newTestRDAPProvider 创建将 com 指向指定RDAP和WHOIS服务器的数据源，whoisAddr 为空时不回退
+func newTestRDAPProvider(rdapURL, whoisAddr string) *RDAPProvider {
+	client := rdap.NewClient(nil)
+	client.Servers["com"] = rdapURL
+
+	var fallback *WhoisProvider
+	if whoisAddr != "" {
+		whoisClient := whois.NewClient()
+		whoisClient.Servers = map[string]string{"com": whoisAddr}
+		whoisClient.DiscoveryServer = ""
+		fallback = NewWhoisProvider(whoisClient)
+	}
+	return NewRDAPProvider(client, fallback)
+}
+
+func TestRDAPProviderNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	attrs, err := newTestRDAPProvider(server.URL, "").Fetch(context.Background(), "free.com")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if attrs["registered"] != false || attrs["registration_source"] != "rdap" {
+		t.Errorf("Fetch() = %v, want registered false from rdap", attrs)
+	}
+}
+
+func TestRDAPProviderFallbackToWhois(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	whoisAddr := startWhoisServer(t, map[string]string{
+		"abc.com": "Domain Name: ABC.COM\r\nRegistrar: Example Registrar\r\n" +
+			"Creation Date: 1990-04-12T04:00:00Z\r\nRegistry Expiry Date: 2027-04-13T04:00:00Z\r\n",
+	})
+
+	attrs, err := newTestRDAPProvider(server.URL, whoisAddr).Fetch(context.Background(), "abc.com")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if attrs["registration_source"] != "whois" || attrs["register_date"] != "1990-04-12" ||
+		attrs["expire_date"] != "2027-04-13" || attrs["registrar"] != "Example Registrar" {
+		t.Errorf("Fetch() = %v", attrs)
+	}
+}
+
+func TestRDAPProviderFallbackFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	// 关闭监听后的地址不可连接，WHOIS查询同样失败
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = newTestRDAPProvider(server.URL, addr).Fetch(context.Background(), "abc.com")
+	if err == nil {
+		t.Fatal("Fetch() error = nil, want joined RDAP and WHOIS errors")
+	}
+	if !strings.Contains(err.Error(), "500") || !strings.Contains(err.Error(), "whois") {
+		t.Errorf("Fetch() error = %v, want both RDAP and WHOIS errors", err)
+	}
+}
+
+func TestRDAPProviderWithoutFallback(t *testing.T) {
+	_, err := NewRDAPProvider(rdap.NewClient(nil), nil).Fetch(context.Background(), "abc.museum")
+	if !errors.Is(err, rdap.ErrNoService) {
+		t.Errorf("Fetch() error = %v, want ErrNoService", err)
+	}
+}
+
+func TestDefaultProviderRegistryDisablesLiveProviders(t *testing.T) {
+	for _, mode := range []string{config.MockModeRandom, config.MockModeDeterministic} {
+		cfg := config.Default().Dynamic
+		cfg.MockMode = mode
+		r, err := NewDefaultProviderRegistry(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 没有配置文件时不访问网络
+		names := r.Names()
+		for _, name := range config.LiveProviders {
+			if enabled, ok := names[name]; !ok || enabled {
+				t.Errorf("%s: 数据源 %s 注册 %v、启用 %v，want 已注册且禁用", mode, name, ok, enabled)
+			}
+		}
+		if !names["mock_whois"] || !names["dictionary"] {
+			t.Errorf("%s: Names() = %v", mode, names)
+		}
+	}
+
+	r, err := NewDefaultProviderRegistry(config.Default().Dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Configure(map[string]bool{"rdap": true}); err != nil {
+		t.Fatal(err)
+	}
+	if names := r.Names(); !names["rdap"] || names["whois"] {
+		t.Errorf("Configure(rdap) 后 Names() = %v", names)
+	}
+}
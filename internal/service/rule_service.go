@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"domainweb/internal/expr"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+	"domainweb/internal/rules"
+)
+
+// ErrRuleNotFound 表示估价规则不存在
+var ErrRuleNotFound = errors.New("估价规则不存在")
+
+// RuleService 管理动态属性估价规则，保存前校验规则和条件表达式
+type RuleService struct {
+	repo     repository.DomainRepository
+	registry *ProviderRegistry
+	ruleSets *RuleSetService
+	audit    *AuditService
+}
+
+// NewRuleService 创建一个新的RuleService实例
+func NewRuleService(repo repository.DomainRepository, registry *ProviderRegistry, ruleSets *RuleSetService, audit *AuditService) *RuleService {
+	return &RuleService{repo: repo, registry: registry, ruleSets: ruleSets, audit: audit}
+}
+
+// List 获取所有估价规则，按优先级排序
+func (s *RuleService) List(ctx context.Context) ([]model.ValuationRule, error) {
+	return s.repo.GetValuationRules(ctx)
+}
+
+// Compile 编译条件表达式，可用于在保存规则前检查表达式
+func (s *RuleService) Compile(source string) (*expr.Program, error) {
+	return rules.Compile(source, s.registry.AllKeys())
+}
+
+// Validate 校验估价规则，表达式中只能引用域名字段和已注册数据源的动态属性
+func (s *RuleService) Validate(rule model.ValuationRule) error {
+	return rules.Validate(rule, s.registry.AllKeys())
+}
+
+// Create 校验并保存一条新的估价规则，记录审计日志并创建新的规则集版本
+func (s *RuleService) Create(ctx context.Context, rule *model.ValuationRule, change Change) error {
+	if err := s.Validate(*rule); err != nil {
+		return fmt.Errorf("估价规则无效: %w", err)
+	}
+	s.ruleSets.checkpoint(ctx)
+	if err := s.repo.CreateValuationRule(ctx, rule); err != nil {
+		return err
+	}
+	s.audit.record(change, model.AuditEntityRule, rule.ID, rule.AttributeKey, model.AuditActionCreate, nil, rule)
+	s.ruleSets.record(change, fmt.Sprintf("新增估价规则 %d %s", rule.ID, rule.AttributeKey))
+	return nil
+}
+
+// Delete 删除估价规则，记录审计日志并创建新的规则集版本
+func (s *RuleService) Delete(ctx context.Context, id int64, change Change) error {
+	before, err := s.repo.GetValuationRule(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrRuleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.ruleSets.checkpoint(ctx)
+	err = s.repo.DeleteValuationRule(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrRuleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.audit.record(change, model.AuditEntityRule, id, before.AttributeKey, model.AuditActionDelete, before, nil)
+	s.ruleSets.record(change, fmt.Sprintf("删除估价规则 %d %s", id, before.AttributeKey))
+	return nil
+}
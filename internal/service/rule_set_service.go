@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"domainweb/internal/config"
+	"domainweb/internal/model"
+	"domainweb/internal/repository"
+)
+
+// ErrRuleSetNotFound 表示规则集版本不存在
+var ErrRuleSetNotFound = errors.New("规则集版本不存在")
+
+// RuleSetService 管理规则集版本
+// 规则集是域名属性、估价规则和估价基数的不可变快照，按内容的校验和去重：
+// 每次估价前读取当前规则，内容未出现过时自动创建新版本，因此任何估价结果都能对应到一个版本。
+// 当前规则从 AttributeCatalog 的缓存读取，缓存未刷新时直接返回上次解析的版本
+type RuleSetService struct {
+	repo      repository.RuleSetRepository
+	catalog   *AttributeCatalog
+	audit     *AuditService
+	basePrice float64
+	baseGrade float64
+
+	mu       sync.Mutex
+	versions map[string]model.RuleSet // 按校验和缓存已保存的版本（不含内容）
+
+	currentMu    sync.Mutex
+	currentEntry *catalogEntry  // 上次解析时的缓存内容
+	current      *model.RuleSet // currentEntry 对应的规则集版本
+}
+
+// NewRuleSetService 创建一个新的RuleSetService实例
+func NewRuleSetService(repo repository.RuleSetRepository, catalog *AttributeCatalog, audit *AuditService, cfg config.EstimationConfig) *RuleSetService {
+	return &RuleSetService{
+		repo:      repo,
+		catalog:   catalog,
+		audit:     audit,
+		basePrice: cfg.BasePrice,
+		baseGrade: cfg.BaseGrade,
+		versions:  make(map[string]model.RuleSet),
+	}
+}
+
+// Current 读取当前生效的规则并返回对应的规则集版本
+// 规则被绕过管理后台直接修改时，以 system 身份创建新版本
+func (s *RuleSetService) Current(ctx context.Context) (*model.RuleSet, error) {
+	entry, err := s.catalog.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.currentMu.Lock()
+	defer s.currentMu.Unlock()
+	if entry != s.currentEntry {
+		ruleSet, err := s.resolve(ctx, s.content(entry), Change{Source: model.AuditSourceSystem, Reason: "检测到规则变更"}, "")
+		if err != nil {
+			return nil, err
+		}
+		s.currentEntry, s.current = entry, ruleSet
+	}
+	ruleSet := *s.current
+	return &ruleSet, nil
+}
+
+// Commit 在修改规则后为当前规则创建版本，变更原因为空时使用 comment 作为版本说明
+// 当前规则与已有版本相同时返回已有版本
+func (s *RuleSetService) Commit(ctx context.Context, change Change, comment string) (*model.RuleSet, error) {
+	s.catalog.Invalidate()
+	entry, err := s.catalog.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, s.content(entry), change, comment)
+}
+
+// checkpoint 在修改规则前确保当前规则已有版本，使变更前后的规则都能追溯
+// 先使缓存失效，以免遗漏其他进程尚未刷新到缓存中的修改
+func (s *RuleSetService) checkpoint(ctx context.Context) {
+	s.catalog.Invalidate()
+	if _, err := s.Current(ctx); err != nil {
+		log.Printf("创建规则集版本失败: %v", err)
+	}
+}
+
+// record 在规则修改成功后创建版本，失败时只记录日志
+// 修改已经生效，因此不随请求取消；失败时下次估价会以 system 身份补建版本
+func (s *RuleSetService) record(change Change, comment string) {
+	if _, err := s.Commit(context.Background(), change, comment); err != nil {
+		log.Printf("创建规则集版本失败: %v", err)
+	}
+}
+
+// Get 获取规则集版本及其内容
+func (s *RuleSetService) Get(ctx context.Context, version int64) (*model.RuleSet, error) {
+	ruleSet, err := s.repo.GetRuleSet(ctx, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRuleSetNotFound
+	}
+	return ruleSet, err
+}
+
+// List 获取最近的规则集版本，不含规则内容
+func (s *RuleSetService) List(ctx context.Context, limit int) ([]model.RuleSet, error) {
+	return s.repo.ListRuleSets(ctx, limit)
+}
+
+// content 根据缓存的域名属性和估价规则生成规则内容，不修改缓存
+func (s *RuleSetService) content(entry *catalogEntry) *model.RuleSetContent {
+	// 记录的创建和修改时间不影响估价，不计入规则内容，避免内容未变时产生新版本
+	valuationRules := append([]model.ValuationRule(nil), entry.valuationRules...)
+	for i := range valuationRules {
+		valuationRules[i].CreatedAt = time.Time{}
+		valuationRules[i].UpdatedAt = time.Time{}
+	}
+
+	return &model.RuleSetContent{
+		BasePrice:      s.basePrice,
+		BaseGrade:      s.baseGrade,
+		Attributes:     entry.attributes,
+		ValuationRules: valuationRules,
+	}
+}
+
+// resolve 返回内容对应的规则集版本，不存在时创建并记录审计日志
+func (s *RuleSetService) resolve(ctx context.Context, content *model.RuleSetContent, change Change, comment string) (*model.RuleSet, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("序列化规则集失败: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.versions[checksum]; ok {
+		cached.Content = content
+		return &cached, nil
+	}
+
+	ruleSet, err := s.repo.GetRuleSetByChecksum(ctx, checksum)
+	if errors.Is(err, repository.ErrNotFound) {
+		ruleSet, err = s.create(ctx, checksum, content, change, comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet.Content = nil
+	s.versions[checksum] = *ruleSet
+	ruleSet.Content = content
+	return ruleSet, nil
+}
+
+// create 保存新的规则集版本，审计日志中的变更前的值为此前的最新版本
+func (s *RuleSetService) create(ctx context.Context, checksum string, content *model.RuleSetContent, change Change, comment string) (*model.RuleSet, error) {
+	if change.Reason != "" {
+		comment = change.Reason
+	}
+	ruleSet := &model.RuleSet{
+		Checksum:  checksum,
+		Author:    change.author(),
+		Comment:   comment,
+		CreatedAt: time.Now(),
+		Content:   content,
+	}
+
+	var previous interface{}
+	if latest, err := s.repo.ListRuleSets(ctx, 1); err == nil && len(latest) > 0 {
+		previous = latest[0]
+	}
+
+	if err := s.repo.CreateRuleSet(ctx, ruleSet); err != nil {
+		// 多个进程共用数据库时，其他进程可能已经保存了相同的内容
+		if existing, lookupErr := s.repo.GetRuleSetByChecksum(ctx, checksum); lookupErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	summary := *ruleSet
+	summary.Content = nil
+	s.audit.record(change, model.AuditEntityRuleSet, ruleSet.Version, fmt.Sprintf("v%d", ruleSet.Version),
+		model.AuditActionCreate, previous, summary)
+	return ruleSet, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"domainweb/internal/whois"
+)
+
+// WhoisProvider 通过WHOIS协议获取域名的注册信息
+type WhoisProvider struct {
+	client *whois.Client
+}
+
+// NewWhoisProvider 创建一个新的WhoisProvider实例
+func NewWhoisProvider(client *whois.Client) *WhoisProvider {
+	return &WhoisProvider{client: client}
+}
+
+// Name 返回数据源名称
+func (p *WhoisProvider) Name() string { return "whois" }
+
+// Keys 返回数据源产出的属性键
+func (p *WhoisProvider) Keys() []string {
+	return []string{"registered", "register_date", "expire_date", "updated_date", "registrar", "domain_status", "name_servers"}
+}
+
+// Fetch 查询域名的WHOIS信息，日期统一格式化为 2006-01-02
+func (p *WhoisProvider) Fetch(ctx context.Context, domain string) (map[string]interface{}, error) {
+	record, err := p.client.Lookup(ctx, domain)
+	if errors.Is(err, whois.ErrNotFound) {
+		return map[string]interface{}{"registered": false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return registrationAttributes(record.Registrar, record.CreatedDate, record.ExpiryDate, record.UpdatedDate,
+		record.Status, record.NameServers), nil
+}
+
+// registrationAttributes 将注册信息转换为动态属性
+func registrationAttributes(registrar string, created, expiry, updated time.Time, status, nameServers []string) map[string]interface{} {
+	result := map[string]interface{}{"registered": true}
+	if registrar != "" {
+		result["registrar"] = registrar
+	}
+	if !created.IsZero() {
+		result["register_date"] = created.Format("2006-01-02")
+	}
+	if !expiry.IsZero() {
+		result["expire_date"] = expiry.Format("2006-01-02")
+	}
+	if !updated.IsZero() {
+		result["updated_date"] = updated.Format("2006-01-02")
+	}
+	if len(status) > 0 {
+		result["domain_status"] = status
+	}
+	if len(nameServers) > 0 {
+		result["name_servers"] = nameServers
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+// Package testutil 提供测试共用的数据库夹具
+package testutil
+
+import (
+	"database/sql"
+	"testing"
+
+	"domainweb/internal/migrate"
+	"domainweb/internal/repository"
+)
+
+// OpenSQLite 打开一个测试结束时关闭的SQLite内存数据库，不应用迁移
+func OpenSQLite(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := repository.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// OpenMigratedSQLite 打开已应用全部迁移的SQLite内存数据库，测试结束时关闭
+func OpenMigratedSQLite(t testing.TB) *sql.DB {
+	t.Helper()
+	db := OpenSQLite(t)
+	m, err := migrate.New(db, repository.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("应用迁移失败: %v", err)
+	}
+	return db
+}
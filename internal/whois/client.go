@@ -0,0 +1,221 @@
+// Package whois 实现基于TCP 43端口的WHOIS查询客户端
+package whois
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort 是WHOIS协议的默认端口
+const DefaultPort = "43"
+
+// IANAServer 是用于发现顶级域WHOIS服务器的IANA服务器
+const IANAServer = "whois.iana.org"
+
+// ErrNotFound 表示WHOIS服务器没有该域名的记录（通常意味着未注册）
+var ErrNotFound = errors.New("whois: 域名未注册或无记录")
+
+// defaultServers 是常见顶级域的WHOIS服务器
+var defaultServers = map[string]string{
+	"com":        "whois.verisign-grs.com",
+	"net":        "whois.verisign-grs.com",
+	"org":        "whois.pir.org",
+	"info":       "whois.nic.info",
+	"biz":        "whois.nic.biz",
+	"cn":         "whois.cnnic.cn",
+	"xn--fiqs8s": "cwhois.cnnic.cn", // 中国
+	"xn--55qx5d": "whois.ngtld.cn",  // 公司
+	"cc":         "ccwhois.verisign-grs.com",
+	"tv":         "tvwhois.verisign-grs.com",
+	"co":         "whois.nic.co",
+	"io":         "whois.nic.io",
+	"ai":         "whois.nic.ai",
+	"me":         "whois.nic.me",
+	"xyz":        "whois.nic.xyz",
+	"top":        "whois.nic.top",
+}
+
+// Client 是WHOIS查询客户端
+type Client struct {
+	// Servers 指定顶级域对应的WHOIS服务器，可带端口，如 "127.0.0.1:4343"
+	Servers map[string]string
+	// DiscoveryServer 用于发现未知顶级域的WHOIS服务器，为空时不做发现
+	DiscoveryServer string
+	// Timeout 是单次查询（含连接）的超时时间
+	Timeout time.Duration
+	// MaxReferrals 是最多跟随的注册商WHOIS服务器转介次数
+	MaxReferrals int
+	// Dial 用于建立TCP连接，为空时使用net.Dialer，测试时可替换
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mu         sync.Mutex
+	discovered map[string]string // 通过IANA发现的服务器缓存
+}
+
+// NewClient 创建使用内置服务器列表的Client实例
+func NewClient() *Client {
+	servers := make(map[string]string, len(defaultServers))
+	for tld, server := range defaultServers {
+		servers[tld] = server
+	}
+	return &Client{
+		Servers:         servers,
+		DiscoveryServer: IANAServer,
+		Timeout:         10 * time.Second,
+		MaxReferrals:    2,
+	}
+}
+
+// Lookup 查询域名的WHOIS信息，并跟随注册商WHOIS服务器的转介
+// domain 需为punycode形式的可注册域名，如 abc.com、xn--fiq228c.com
+func (c *Client) Lookup(ctx context.Context, domain string) (*Record, error) {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+
+	server, err := c.ServerFor(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Query(ctx, server, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	record := Parse(raw)
+	if record.NotFound {
+		return nil, ErrNotFound
+	}
+	record.Server = server
+
+	// 跟随转介：注册局返回的精简记录中通常包含注册商的WHOIS服务器
+	visited := map[string]bool{serverAddr(server): true}
+	for i := 0; i < c.MaxReferrals; i++ {
+		referral := record.ReferralServer
+		if referral == "" || visited[serverAddr(referral)] {
+			break
+		}
+		visited[serverAddr(referral)] = true
+
+		raw, err := c.Query(ctx, referral, domain)
+		if err != nil {
+			// 注册商服务器不可用时保留注册局的数据
+			break
+		}
+		referred := Parse(raw)
+		if referred.NotFound {
+			break
+		}
+		referred.Server = referral
+		record = record.merge(referred)
+	}
+
+	return record, nil
+}
+
+// ServerFor 返回域名对应的WHOIS服务器
+func (c *Client) ServerFor(ctx context.Context, domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+
+	// 优先匹配最长的后缀，如 com.cn 再到 cn
+	for i := 1; i < len(labels); i++ {
+		if server, ok := c.Servers[strings.Join(labels[i:], ".")]; ok {
+			return server, nil
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	c.mu.Lock()
+	server, ok := c.discovered[tld]
+	c.mu.Unlock()
+	if ok {
+		return server, nil
+	}
+
+	if c.DiscoveryServer == "" {
+		return "", fmt.Errorf("whois: 未配置顶级域 %s 的WHOIS服务器", tld)
+	}
+
+	raw, err := c.Query(ctx, c.DiscoveryServer, tld)
+	if err != nil {
+		return "", fmt.Errorf("whois: 发现顶级域 %s 的WHOIS服务器失败: %w", tld, err)
+	}
+	server = Parse(raw).ReferralServer
+	if server == "" {
+		return "", fmt.Errorf("whois: 顶级域 %s 没有WHOIS服务器", tld)
+	}
+
+	c.mu.Lock()
+	if c.discovered == nil {
+		c.discovered = make(map[string]string)
+	}
+	c.discovered[tld] = server
+	c.mu.Unlock()
+
+	return server, nil
+}
+
+// Query 向指定的WHOIS服务器发送查询并返回原始响应
+func (c *Client) Query(ctx context.Context, server, query string) (string, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	addr := serverAddr(server)
+
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("whois: 连接 %s 失败: %w", addr, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// 上下文取消时关闭连接以中断阻塞的读写
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", fmt.Errorf("whois: 发送查询到 %s 失败: %w", addr, err)
+	}
+
+	// 响应大小限制为1MB，防止异常服务器占用内存
+	data, err := io.ReadAll(io.LimitReader(conn, 1<<20))
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("whois: 读取 %s 的响应失败: %w", addr, err)
+	}
+
+	return string(data), nil
+}
+
+// serverAddr 返回带端口的服务器地址，未指定端口时使用43端口
+func serverAddr(server string) string {
+	server = strings.ToLower(server)
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(server, DefaultPort)
+}
@@ -0,0 +1,216 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer 是按查询内容返回固定响应的WHOIS服务器，并记录收到的查询
+type fakeServer struct {
+	addr      string
+	responses map[string]string
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// startFakeServer 在本地随机端口启动fakeServer，测试结束时关闭
+func startFakeServer(t *testing.T, responses map[string]string) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeServer{addr: ln.Addr().String(), responses: responses}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	query := strings.TrimSpace(line)
+
+	s.mu.Lock()
+	s.queries = append(s.queries, query)
+	s.mu.Unlock()
+
+	conn.Write([]byte(s.responses[query]))
+}
+
+// Queries 返回收到的查询
+func (s *fakeServer) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+// newTestClient 创建只使用指定服务器、不做IANA发现的客户端
+func newTestClient(servers map[string]string) *Client {
+	c := NewClient()
+	c.Servers = servers
+	c.DiscoveryServer = ""
+	c.Timeout = 5 * time.Second
+	return c
+}
+
+func TestLookupFollowsReferral(t *testing.T) {
+	registrar := startFakeServer(t, map[string]string{
+		"abc.com": "Domain Name: abc.com\r\n" +
+			"Registrar: Example Registrar, Inc.\r\n" +
+			"Registrar Registration Expiration Date: 2027-05-01T00:00:00Z\r\n" +
+			"Name Server: NS3.EXAMPLE.NET\r\n",
+	})
+	registry := startFakeServer(t, map[string]string{
+		"abc.com": "   Domain Name: ABC.COM\r\n" +
+			"   Registrar WHOIS Server: " + registrar.addr + "\r\n" +
+			"   Updated Date: 2024-03-01T10:00:00Z\r\n" +
+			"   Creation Date: 1990-04-12T04:00:00Z\r\n" +
+			"   Registry Expiry Date: 2027-04-13T04:00:00Z\r\n" +
+			"   Registrar: Registry Sponsor\r\n" +
+			"   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited\r\n" +
+			"   Name Server: NS1.EXAMPLE.NET\r\n" +
+			"   Name Server: NS2.EXAMPLE.NET\r\n",
+	})
+
+	record, err := newTestClient(map[string]string{"com": registry.addr}).Lookup(context.Background(), "ABC.com.")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	// 注册商的非空字段优先，注册局的其余字段保留
+	if record.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Registrar = %q", record.Registrar)
+	}
+	if want := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC); !record.ExpiryDate.Equal(want) {
+		t.Errorf("ExpiryDate = %v, want %v", record.ExpiryDate, want)
+	}
+	if want := time.Date(1990, 4, 12, 4, 0, 0, 0, time.UTC); !record.CreatedDate.Equal(want) {
+		t.Errorf("CreatedDate = %v, want %v", record.CreatedDate, want)
+	}
+	if got := strings.Join(record.NameServers, ","); got != "ns1.example.net,ns2.example.net,ns3.example.net" {
+		t.Errorf("NameServers = %s", got)
+	}
+	if got := strings.Join(record.Status, ","); got != "clientTransferProhibited" {
+		t.Errorf("Status = %s", got)
+	}
+	if record.Server != registrar.addr {
+		t.Errorf("Server = %q, want %q", record.Server, registrar.addr)
+	}
+}
+
+func TestLookupReferralLimit(t *testing.T) {
+	registrar := startFakeServer(t, map[string]string{"abc.com": "Registrar: Example Registrar, Inc.\r\n"})
+	registry := startFakeServer(t, map[string]string{
+		"abc.com": "Domain Name: ABC.COM\r\nRegistrar WHOIS Server: " + registrar.addr + "\r\nRegistrar: Registry Sponsor\r\n",
+	})
+
+	client := newTestClient(map[string]string{"com": registry.addr})
+	client.MaxReferrals = 0
+	record, err := client.Lookup(context.Background(), "abc.com")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if record.Registrar != "Registry Sponsor" || len(registrar.Queries()) != 0 {
+		t.Errorf("Registrar = %q, registrar queries = %v, want no referral", record.Registrar, registrar.Queries())
+	}
+}
+
+func TestLookupDiscoversServer(t *testing.T) {
+	nic := startFakeServer(t, map[string]string{
+		"abc.example": "Domain Name: abc.example\r\nCreated: 2001-02-03\r\nExpires: 2031-02-03\r\n",
+	})
+	iana := startFakeServer(t, map[string]string{
+		"example": "% IANA WHOIS server\r\n\r\ndomain:       EXAMPLE\r\nrefer:        " + nic.addr + "\r\n",
+	})
+
+	client := newTestClient(map[string]string{})
+	client.DiscoveryServer = iana.addr
+	for i := 0; i < 2; i++ {
+		record, err := client.Lookup(context.Background(), "abc.example")
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if want := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC); !record.CreatedDate.Equal(want) {
+			t.Errorf("CreatedDate = %v, want %v", record.CreatedDate, want)
+		}
+	}
+
+	// 发现的服务器被缓存，只向IANA查询一次
+	if got := iana.Queries(); len(got) != 1 || got[0] != "example" {
+		t.Errorf("IANA queries = %v, want [example]", got)
+	}
+}
+
+func TestServerForWithoutDiscovery(t *testing.T) {
+	client := newTestClient(map[string]string{"cn": "whois.cn.example", "com.cn": "whois.comcn.example"})
+
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{"abc.cn", "whois.cn.example", false},
+		{"abc.com.cn", "whois.comcn.example", false},
+		{"abc.example", "", true},
+	}
+	for _, tt := range tests {
+		got, err := client.ServerFor(context.Background(), tt.domain)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ServerFor(%q) = %q, %v, want %q, wantErr %v", tt.domain, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLookupNotFound(t *testing.T) {
+	responses := map[string]string{
+		"free.com":  "No match for \"FREE.COM\".\r\n>>> Last update of whois database: 2026-10-01T00:00:00Z <<<\r\n",
+		"free.org":  "Domain not found.\r\n",
+		"free.cn":   "No matching record.\r\n",
+		"free.io":   "NOT FOUND\r\n",
+		"free.de":   "Domain: free.de\r\nStatus: free\r\n",
+		"free.info": "The queried object does not exist: DOMAIN NOT FOUND\r\n",
+	}
+	server := startFakeServer(t, responses)
+	client := newTestClient(map[string]string{"com": server.addr, "org": server.addr, "cn": server.addr,
+		"io": server.addr, "de": server.addr, "info": server.addr})
+
+	for domain := range responses {
+		if _, err := client.Lookup(context.Background(), domain); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup(%q) error = %v, want ErrNotFound", domain, err)
+		}
+	}
+}
+
+func TestLookupConnectionError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = newTestClient(map[string]string{"com": addr}).Lookup(context.Background(), "abc.com")
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() error = %v, want connection error", err)
+	}
+}
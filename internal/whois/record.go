@@ -0,0 +1,203 @@
+package whois
+
+import (
+	"bufio"
+	"strings"
+	"time"
+)
+
+// Record 表示解析后的WHOIS记录
+type Record struct {
+	Domain         string    // 域名
+	Registrar      string    // 注册商
+	CreatedDate    time.Time // 注册日期
+	ExpiryDate     time.Time // 到期日期
+	UpdatedDate    time.Time // 最后更新日期
+	Status         []string  // 域名状态，如 clientTransferProhibited
+	NameServers    []string  // 域名服务器
+	ReferralServer string    // 注册商WHOIS服务器（转介）
+	Server         string    // 返回该记录的WHOIS服务器
+	NotFound       bool      // 服务器明确表示无此记录
+	Raw            string    // 原始响应
+}
+
+// 常见响应格式中各字段的键名（小写）
+var (
+	domainKeys    = []string{"domain name", "domain", "domain_name"}
+	registrarKeys = []string{"registrar", "sponsoring registrar", "registrar name", "registrar-name"}
+	createdKeys   = []string{"creation date", "created", "created on", "registration time", "registered on",
+		"registration date", "domain registration date", "domain record activated", "created date"}
+	expiryKeys = []string{"registry expiry date", "registrar registration expiration date", "expiration date",
+		"expiration time", "expiry date", "expires on", "expires", "paid-till", "domain expiration date",
+		"registry expiration date"}
+	updatedKeys    = []string{"updated date", "last updated on", "last modified", "last-update", "changed", "last updated"}
+	statusKeys     = []string{"domain status", "status", "state"}
+	nameServerKeys = []string{"name server", "nameserver", "nserver", "name servers"}
+	referralKeys   = []string{"registrar whois server", "whois server", "whois", "refer", "referralserver"}
+)
+
+// 表示域名未注册的常见响应片段（小写）
+var notFoundMarkers = []string{
+	"no match for",
+	"not found",
+	"no matching record",
+	"no data found",
+	"no entries found",
+	"domain not found",
+	"the queried object does not exist",
+	"status: free",
+	"status: available",
+}
+
+// 常见的日期格式
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"2006.01.02 15:04:05",
+	"02-Jan-2006",
+	"02-jan-2006",
+	"2-Jan-2006",
+	"02-Jan-2006 15:04:05 MST",
+	"January 2 2006",
+	"Mon Jan 2 15:04:05 MST 2006",
+	"20060102",
+}
+
+// Parse 解析WHOIS服务器的原始响应
+// 支持 "键: 值" 形式的常见格式（Verisign、CNNIC、ICANN标准格式、IANA等）
+func Parse(raw string) *Record {
+	record := &Record{Raw: raw}
+	lower := strings.ToLower(raw)
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">>>") {
+			continue
+		}
+
+		idx := strings.Index(line, ":")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:idx]))
+		value := strings.TrimSpace(line[idx+1:])
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case record.Domain == "" && hasKey(domainKeys, key):
+			record.Domain = strings.ToLower(value)
+		case record.Registrar == "" && hasKey(registrarKeys, key):
+			record.Registrar = value
+		case record.CreatedDate.IsZero() && hasKey(createdKeys, key):
+			record.CreatedDate = parseDate(value)
+		case record.ExpiryDate.IsZero() && hasKey(expiryKeys, key):
+			record.ExpiryDate = parseDate(value)
+		case record.UpdatedDate.IsZero() && hasKey(updatedKeys, key):
+			record.UpdatedDate = parseDate(value)
+		case hasKey(statusKeys, key):
+			// 状态值后常附带说明链接，如 "clientTransferProhibited https://icann.org/epp#..."
+			record.Status = appendUnique(record.Status, strings.Fields(value)[0])
+		case hasKey(nameServerKeys, key):
+			record.NameServers = appendUnique(record.NameServers, strings.ToLower(strings.Fields(value)[0]))
+		case record.ReferralServer == "" && hasKey(referralKeys, key):
+			record.ReferralServer = referralHost(value)
+		}
+	}
+
+	// 未注册的响应可能回显查询的域名（如 DENIC 的 "Domain: x.de" 和 "Status: free"），因此不以域名字段判断
+	if record.Registrar == "" && record.CreatedDate.IsZero() {
+		for _, marker := range notFoundMarkers {
+			if strings.Contains(lower, marker) {
+				record.NotFound = true
+				break
+			}
+		}
+	}
+
+	return record
+}
+
+// merge 使用注册商返回的记录补充注册局记录，注册商的非空字段优先
+func (r *Record) merge(other *Record) *Record {
+	merged := *r
+	if other.Registrar != "" {
+		merged.Registrar = other.Registrar
+	}
+	if !other.CreatedDate.IsZero() {
+		merged.CreatedDate = other.CreatedDate
+	}
+	if !other.ExpiryDate.IsZero() {
+		merged.ExpiryDate = other.ExpiryDate
+	}
+	if !other.UpdatedDate.IsZero() {
+		merged.UpdatedDate = other.UpdatedDate
+	}
+	for _, s := range other.Status {
+		merged.Status = appendUnique(merged.Status, s)
+	}
+	for _, ns := range other.NameServers {
+		merged.NameServers = appendUnique(merged.NameServers, ns)
+	}
+	merged.ReferralServer = other.ReferralServer
+	merged.Server = other.Server
+	merged.Raw = r.Raw + "\n" + other.Raw
+	return &merged
+}
+
+// parseDate 按常见格式解析日期，无法解析时返回零值
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	// 去除部分服务器附加的说明，如 "2025-01-01 (YYYY-MM-DD)"
+	if idx := strings.Index(value, " ("); idx != -1 {
+		value = value[:idx]
+	}
+	value = strings.TrimSuffix(value, " UTC")
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// referralHost 从转介字段中提取服务器地址，如 "whois://whois.example.com" -> "whois.example.com"
+func referralHost(value string) string {
+	value = strings.Fields(value)[0]
+	value = strings.TrimPrefix(value, "whois://")
+	value = strings.TrimPrefix(value, "rwhois://")
+	// 部分注册局会返回网址而不是WHOIS服务器
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(value, "/"))
+}
+
+func hasKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,85 @@
+package whois
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"1997-09-15T04:00:00Z", time.Date(1997, 9, 15, 4, 0, 0, 0, time.UTC)},
+		{"1997-09-15T04:00:00.123Z", time.Date(1997, 9, 15, 4, 0, 0, 123000000, time.UTC)},
+		{"1997-09-15T07:00:00+08:00", time.Date(1997, 9, 14, 23, 0, 0, 0, time.UTC)},
+		{"1997-09-15T04:00:00", time.Date(1997, 9, 15, 4, 0, 0, 0, time.UTC)},
+		{"2003-03-17 12:20:05", time.Date(2003, 3, 17, 12, 20, 5, 0, time.UTC)},
+		{"2003-03-17 12:20:05 UTC", time.Date(2003, 3, 17, 12, 20, 5, 0, time.UTC)},
+		{"2003-03-17", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"2003-03-17 (YYYY-MM-DD)", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"2003.03.17", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"2003/03/17", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"2003.03.17 12:20:05", time.Date(2003, 3, 17, 12, 20, 5, 0, time.UTC)},
+		{"17-Mar-2003", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"17-mar-2003", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"7-Mar-2003", time.Date(2003, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"March 17 2003", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"20030317", time.Date(2003, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"before 1995", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseDate(tt.value); !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseCNNIC(t *testing.T) {
+	raw := "Domain Name: abc.cn\r\n" +
+		"ROID: 20030312s10001s00012345-cn\r\n" +
+		"Domain Status: ok\r\n" +
+		"Sponsoring Registrar: 北京某某科技有限公司\r\n" +
+		"Name Server: ns1.abc.cn\r\n" +
+		"Registration Time: 2003-03-17 12:20:05\r\n" +
+		"Expiration Time: 2030-03-17 12:48:36\r\n"
+
+	record := Parse(raw)
+	if record.NotFound {
+		t.Fatal("NotFound = true")
+	}
+	if record.Domain != "abc.cn" || record.Registrar != "北京某某科技有限公司" {
+		t.Errorf("Domain, Registrar = %q, %q", record.Domain, record.Registrar)
+	}
+	if want := time.Date(2003, 3, 17, 12, 20, 5, 0, time.UTC); !record.CreatedDate.Equal(want) {
+		t.Errorf("CreatedDate = %v, want %v", record.CreatedDate, want)
+	}
+	if want := time.Date(2030, 3, 17, 12, 48, 36, 0, time.UTC); !record.ExpiryDate.Equal(want) {
+		t.Errorf("ExpiryDate = %v, want %v", record.ExpiryDate, want)
+	}
+}
+
+func TestParseNotFoundIgnoredForRegisteredRecord(t *testing.T) {
+	// 已注册记录的说明文字中可能出现 not found 等字样
+	raw := "Domain Name: ABC.COM\r\nCreation Date: 1990-04-12T04:00:00Z\r\n" +
+		"NOTICE: if the registrar is not found, contact the registry.\r\n"
+	if Parse(raw).NotFound {
+		t.Error("NotFound = true, want false for a registered record")
+	}
+}
+
+func TestReferralHost(t *testing.T) {
+	tests := map[string]string{
+		"whois.example.com":                     "whois.example.com",
+		"whois://Whois.Example.com/":            "whois.example.com",
+		"rwhois://rwhois.example.com:4321":      "rwhois.example.com:4321",
+		"https://www.example.com/whois":         "",
+		"whois.example.com (registrar website)": "whois.example.com",
+	}
+	for value, want := range tests {
+		if got := referralHost(value); got != want {
+			t.Errorf("referralHost(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -11,15 +11,13 @@
 package main
 
 import (
-	"fmt"
 	"log"
 
 	"domainweb/cmd"
 )
 
 func main() {
-	fmt.Println("域名估价系统启动中...")
 	if err := cmd.Execute(); err != nil {
-		log.Fatalf("程序启动失败: %v", err)
+		log.Fatalf("执行失败: %v", err)
 	}
 }
@@ -1,3 +1,6 @@
+-- 手动初始化脚本，与 internal/migrate/migrations/mysql/0001_init.up.sql 保持一致
+-- 推荐使用 domainweb migrate up 管理表结构
+
 -- 创建数据库
 CREATE DATABASE IF NOT EXISTS domainweb CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
 
@@ -54,6 +57,8 @@ INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, gra
 ('co后缀', '基础属性', 1.10, -0.2, 'co', NOW(), NOW()),
 ('io后缀', '基础属性', 1.80, 0.1, 'io', NOW(), NOW()),
 ('ai后缀', '基础属性', 2.50, 0.3, 'ai', NOW(), NOW()),
+('中国后缀', '基础属性', 1.10, -0.1, '中国', NOW(), NOW()),
+('公司后缀', '基础属性', 0.90, -0.2, '公司', NOW(), NOW()),
 
 -- 长度属性
 ('2位长度', '基础属性', 8.50, 1.0, '2', NOW(), NOW()),
@@ -70,6 +75,10 @@ INSERT INTO domain_attributes (attribute_name, attribute_type, price_factor, gra
 ('纯字母结构', '基础属性', 1.26, 0.31, '纯字母', NOW(), NOW()),
 ('数字字母混合结构', '基础属性', 1.15, 0.2, '数字字母混合', NOW(), NOW()),
 ('含连字符结构', '基础属性', 0.85, -0.1, '含连字符', NOW(), NOW()),
+('纯汉字结构', '基础属性', 1.60, 0.4, '纯汉字', NOW(), NOW()),
+('汉字字母混合结构', '基础属性', 0.95, 0.0, '汉字字母混合', NOW(), NOW()),
+('汉字数字混合结构', '基础属性', 1.00, 0.05, '汉字数字混合', NOW(), NOW()),
+('汉字字母数字混合结构', '基础属性', 0.85, -0.1, '汉字字母数字混合', NOW(), NOW()),
 ('其他结构', '基础属性', 0.75, -0.2, '其他', NOW(), NOW()),
 
 -- 其他属性（示例）